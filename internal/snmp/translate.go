@@ -0,0 +1,191 @@
+package snmp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// wellKnownModule is the pseudo module gosmi uses for the ccitt, iso and
+// joint-iso-ccitt root nodes.
+const wellKnownModule = "<well-known>"
+
+// The gosmi library keeps a single global MIB tree which is not safe for
+// concurrent use, so all accesses are serialized.
+var (
+	gosmiLock   sync.Mutex
+	gosmiInit   sync.Once
+	loadedPaths = map[string]bool{}
+)
+
+// LoadMibsFromPath loads all MIB modules found in the given directories and
+// their subdirectories.  Each file is expected to contain the module its
+// name, without extension, refers to.  Directories that were loaded before
+// are skipped, so this is safe to call from every plugin instance.
+func LoadMibsFromPath(paths []string, log telegraf.Logger) error {
+	gosmiLock.Lock()
+	defer gosmiLock.Unlock()
+
+	gosmiInit.Do(gosmi.Init)
+
+	for _, root := range paths {
+		root = filepath.Clean(root)
+		if loadedPaths[root] {
+			continue
+		}
+
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			log.Debugf("MIB path %q does not exist, skipping", root)
+			continue
+		}
+
+		var modules []string
+		seen := map[string]bool{}
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				gosmi.AppendPath(path)
+				return nil
+			}
+
+			name := strings.SplitN(info.Name(), ".", 2)[0]
+			if name == "" || seen[name] {
+				return nil
+			}
+			seen[name] = true
+			modules = append(modules, name)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("reading MIB path %q: %w", root, err)
+		}
+
+		for _, module := range modules {
+			if _, err := gosmi.LoadModule(module); err != nil {
+				log.Warnf("Loading MIB module %q failed: %v", module, err)
+			}
+		}
+		loadedPaths[root] = true
+	}
+
+	return nil
+}
+
+// TranslateOid resolves the given OID, which can be numeric, textual or a
+// mix of both, using the loaded MIB modules.  It returns the name of the
+// module defining the OID, the numeric OID, the name of the OID including any
+// trailing index and the conversion implied by its textual convention.
+// Numeric OIDs not defined in any module are returned as they are.
+func TranslateOid(oid string) (mibName string, oidNum string, oidText string, conversion string, err error) {
+	gosmiLock.Lock()
+	defer gosmiLock.Unlock()
+
+	if i := strings.Index(oid, "::"); i != -1 {
+		mibName = oid[:i]
+		name, suffix := oid[i+2:], ""
+		if j := strings.Index(name, "."); j != -1 {
+			name, suffix = name[:j], name[j:]
+		}
+
+		module, err := gosmi.GetModule(mibName)
+		if err != nil {
+			return "", "", "", "", fmt.Errorf("unknown MIB module %q", mibName)
+		}
+		node, err := gosmi.GetNode(name, module)
+		if err != nil {
+			return "", "", "", "", fmt.Errorf("unknown object %q in MIB module %q", name, mibName)
+		}
+		return mibName, "." + node.RenderNumeric() + suffix, node.Name + suffix, nodeConversion(node), nil
+	}
+
+	subids, err := resolveOid(oid)
+	if err != nil {
+		return "", "", "", "", err
+	}
+	oidNum = "." + subids.String()
+
+	node, err := gosmi.GetNodeByOID(subids)
+	if err != nil || node.GetModule().Name == wellKnownModule {
+		return "", oidNum, oid, "", nil
+	}
+
+	oidText = node.Name
+	for _, subid := range subids[node.OidLen:] {
+		oidText += "." + strconv.FormatUint(uint64(subid), 10)
+	}
+	return node.GetModule().Name, oidNum, oidText, nodeConversion(node), nil
+}
+
+// TableColumns returns the names of the accessible columns of the table with
+// the given numeric OID, together with the set of columns used as the table
+// index.
+func TableColumns(oidNum string) (columns []string, index map[string]bool, err error) {
+	gosmiLock.Lock()
+	defer gosmiLock.Unlock()
+
+	subids, err := types.OidFromString(strings.TrimPrefix(oidNum, "."))
+	if err != nil {
+		return nil, nil, err
+	}
+	node, err := gosmi.GetNodeByOID(subids)
+	if err != nil || int(node.OidLen) != len(subids) || node.Kind != types.NodeTable {
+		return nil, nil, fmt.Errorf("%s is not a table", oidNum)
+	}
+
+	table := node.AsTable()
+	index = make(map[string]bool, len(table.Index))
+	for _, col := range table.Index {
+		index[col.Name] = true
+	}
+	for _, name := range table.ColumnOrder {
+		if table.Columns[name].Access == types.AccessNotAccessible {
+			continue
+		}
+		columns = append(columns, name)
+	}
+	return columns, index, nil
+}
+
+// resolveOid converts an OID which may contain object names, such as
+// ".iso.3.6", into its numeric form.
+func resolveOid(oid string) (types.Oid, error) {
+	var subids types.Oid
+	for _, part := range strings.Split(strings.TrimPrefix(oid, "."), ".") {
+		if n, err := strconv.ParseUint(part, 10, 32); err == nil {
+			subids = append(subids, types.SmiSubId(n))
+			continue
+		}
+
+		node, err := gosmi.GetNode(part)
+		if err != nil {
+			return nil, fmt.Errorf("unknown object %q in OID %q", part, oid)
+		}
+		subids = append(types.Oid{}, node.Oid...)
+	}
+	return subids, nil
+}
+
+// nodeConversion returns the conversion for the textual convention of the
+// node's syntax.
+func nodeConversion(node gosmi.SmiNode) string {
+	if node.Type == nil {
+		return ""
+	}
+
+	switch node.Type.Name {
+	case "MacAddress", "PhysAddress":
+		return "hwaddr"
+	case "InetAddressIPv4", "InetAddressIPv6", "InetAddress", "IPSIpAddress":
+		return "ipaddr"
+	}
+	return ""
+}
@@ -2,9 +2,13 @@ package all
 
 import (
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/aggregators/basicstats"
+	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/aggregators/derivative"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/aggregators/final"
+	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/aggregators/heartbeat"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/aggregators/histogram"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/aggregators/merge"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/aggregators/minmax"
+	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/aggregators/quantile"
+	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/aggregators/starlark"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/aggregators/valuecounter"
 )
@@ -0,0 +1,283 @@
+package derivative
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/aggregators"
+)
+
+const (
+	modePeriod = "period"
+	modeSample = "sample"
+)
+
+type Derivative struct {
+	Suffix      string          `toml:"suffix"`
+	Mode        string          `toml:"mode"`
+	CounterBits int             `toml:"counter_bits"`
+	MaxRollOver int             `toml:"max_roll_over"`
+	Log         telegraf.Logger `toml:"-"`
+
+	cache map[uint64]*series
+}
+
+type series struct {
+	name     string
+	tags     map[string]string
+	fields   map[string]*fieldState
+	updated  bool
+	rollOver int
+}
+
+type fieldState struct {
+	last sample
+
+	// Sum of the deltas and elapsed time over the period, intervals with a
+	// counter reset are left out.
+	delta   float64
+	elapsed time.Duration
+
+	// Rates between the samples in the period.
+	rates []rate
+}
+
+type sample struct {
+	value    float64
+	integer  uint64
+	integral bool
+	time     time.Time
+}
+
+type rate struct {
+	value float64
+	time  time.Time
+}
+
+var sampleConfig = `
+  ## General Aggregator Arguments:
+  ## The period on which to flush & clear the aggregator.
+  period = "30s"
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = false
+
+  ## Suffix appended to the field names of the rates.
+  # suffix = "_rate"
+
+  ## Rate computation:
+  ##   period - emit the per second rate over the period
+  ##   sample - emit the per second rate between each two samples
+  # mode = "period"
+
+  ## Size of the counters in bits, a decrease of a counter is handled as
+  ## wrap around if the counter passed its maximum value and as counter reset
+  ## otherwise.  Rates are not computed for resets.  Set to 0 to handle all
+  ## decreases as reset, or to -1 to compute negative rates for gauges.
+  # counter_bits = 64
+
+  ## Number of periods without new samples the last sample of a series is
+  ## kept to compute the rate with the next sample.  Set to 0 to only use
+  ## samples within a period.
+  # max_roll_over = 10
+`
+
+func (d *Derivative) SampleConfig() string {
+	return sampleConfig
+}
+
+func (d *Derivative) Description() string {
+	return "Compute the rate of change of counter fields per series."
+}
+
+func (d *Derivative) Init() error {
+	switch d.Mode {
+	case "":
+		d.Mode = modePeriod
+	case modePeriod, modeSample:
+	default:
+		return fmt.Errorf("unknown mode %q", d.Mode)
+	}
+
+	switch d.CounterBits {
+	case -1, 0, 32, 64:
+	default:
+		return fmt.Errorf("counter_bits must be one of -1, 0, 32 or 64")
+	}
+
+	if d.MaxRollOver < 0 {
+		return fmt.Errorf("max_roll_over must not be negative")
+	}
+
+	d.cache = make(map[uint64]*series)
+	return nil
+}
+
+func (d *Derivative) Add(in telegraf.Metric) {
+	id := in.HashID()
+	s, ok := d.cache[id]
+	if !ok {
+		s = &series{
+			name:   in.Name(),
+			tags:   in.Tags(),
+			fields: make(map[string]*fieldState),
+		}
+		d.cache[id] = s
+	}
+	s.updated = true
+
+	for _, field := range in.FieldList() {
+		cur, ok := newSample(field.Value, in.Time())
+		if !ok {
+			continue
+		}
+
+		state, ok := s.fields[field.Key]
+		if !ok {
+			s.fields[field.Key] = &fieldState{last: cur}
+			continue
+		}
+
+		prev := state.last
+		elapsed := cur.time.Sub(prev.time)
+		if elapsed <= 0 {
+			// Out of order or duplicate samples are ignored.
+			continue
+		}
+		state.last = cur
+
+		delta, ok := d.delta(prev, cur)
+		if !ok {
+			d.Log.Debugf("Counter reset of field %q in %q", field.Key, s.name)
+			continue
+		}
+
+		state.delta += delta
+		state.elapsed += elapsed
+		if d.Mode == modeSample {
+			state.rates = append(state.rates, rate{
+				value: delta / elapsed.Seconds(),
+				time:  cur.time,
+			})
+		}
+	}
+}
+
+// delta returns the difference between the samples, handling counter wrap
+// arounds.  It returns false if the counter was reset.
+func (d *Derivative) delta(prev, cur sample) (float64, bool) {
+	if cur.integral && prev.integral && cur.integer >= prev.integer {
+		return float64(cur.integer - prev.integer), true
+	}
+	if cur.value >= prev.value || d.CounterBits < 0 {
+		return cur.value - prev.value, true
+	}
+	if d.CounterBits == 0 || !cur.integral || !prev.integral {
+		return 0, false
+	}
+
+	max := uint64(math.MaxUint64)
+	if d.CounterBits == 32 {
+		max = math.MaxUint32
+	}
+	if prev.integer > max {
+		return 0, false
+	}
+
+	// A wrapped counter passed its maximum since the previous sample, a large
+	// distance to the maximum is more likely a reset.
+	wrapped := (max - prev.integer) + cur.integer + 1
+	if wrapped > max/2 {
+		return 0, false
+	}
+	return float64(wrapped), true
+}
+
+func (d *Derivative) Push(acc telegraf.Accumulator) {
+	for _, s := range d.cache {
+		switch d.Mode {
+		case modePeriod:
+			fields := make(map[string]interface{}, len(s.fields))
+			var tm time.Time
+			for k, state := range s.fields {
+				if state.elapsed <= 0 {
+					continue
+				}
+				fields[k+d.Suffix] = state.delta / state.elapsed.Seconds()
+				if state.last.time.After(tm) {
+					tm = state.last.time
+				}
+			}
+			if len(fields) > 0 {
+				acc.AddFields(s.name, fields, s.tags, tm)
+			}
+		case modeSample:
+			// Group the rates of the fields by time.
+			var times []time.Time
+			byTime := make(map[time.Time]map[string]interface{})
+			for k, state := range s.fields {
+				for _, r := range state.rates {
+					fields, ok := byTime[r.time]
+					if !ok {
+						fields = make(map[string]interface{})
+						byTime[r.time] = fields
+						times = append(times, r.time)
+					}
+					fields[k+d.Suffix] = r.value
+				}
+			}
+			sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+			for _, tm := range times {
+				acc.AddFields(s.name, byTime[tm], s.tags, tm)
+			}
+		}
+	}
+}
+
+func (d *Derivative) Reset() {
+	for id, s := range d.cache {
+		if s.updated {
+			s.rollOver = 0
+		} else {
+			s.rollOver++
+		}
+		if d.MaxRollOver == 0 || s.rollOver > d.MaxRollOver {
+			delete(d.cache, id)
+			continue
+		}
+
+		s.updated = false
+		for _, state := range s.fields {
+			state.delta = 0
+			state.elapsed = 0
+			state.rates = state.rates[:0]
+		}
+	}
+}
+
+func newSample(value interface{}, tm time.Time) (sample, bool) {
+	switch v := value.(type) {
+	case float64:
+		return sample{value: v, time: tm}, !math.IsNaN(v) && !math.IsInf(v, 0)
+	case int64:
+		return sample{value: float64(v), integer: uint64(v), integral: v >= 0, time: tm}, true
+	case uint64:
+		return sample{value: float64(v), integer: v, integral: true, time: tm}, true
+	default:
+		return sample{}, false
+	}
+}
+
+func init() {
+	aggregators.Add("derivative", func() telegraf.Aggregator {
+		return &Derivative{
+			Suffix:      "_rate",
+			Mode:        modePeriod,
+			CounterBits: 64,
+			MaxRollOver: 10,
+		}
+	})
+}
@@ -0,0 +1,247 @@
+package derivative
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newDerivative() *Derivative {
+	return &Derivative{
+		Suffix:      "_rate",
+		Mode:        modePeriod,
+		CounterBits: 64,
+		MaxRollOver: 10,
+		Log:         testutil.Logger{},
+	}
+}
+
+func counter(host string, fields map[string]interface{}, sec int64) telegraf.Metric {
+	return testutil.MustMetric("net",
+		map[string]string{"host": host},
+		fields,
+		time.Unix(sec, 0))
+}
+
+func TestPeriod(t *testing.T) {
+	d := newDerivative()
+	require.NoError(t, d.Init())
+
+	d.Add(counter("a", map[string]interface{}{"bytes": int64(100), "packets": uint64(1), "state": "up"}, 0))
+	d.Add(counter("b", map[string]interface{}{"bytes": int64(0)}, 0))
+	d.Add(counter("a", map[string]interface{}{"bytes": int64(300), "packets": uint64(11)}, 10))
+	d.Add(counter("a", map[string]interface{}{"bytes": int64(600), "packets": uint64(21)}, 20))
+
+	acc := testutil.Accumulator{}
+	d.Push(&acc)
+
+	expected := []telegraf.Metric{
+		counter("a", map[string]interface{}{"bytes_rate": 25.0, "packets_rate": 1.0}, 20),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestRollOver(t *testing.T) {
+	d := newDerivative()
+	d.MaxRollOver = 1
+	require.NoError(t, d.Init())
+
+	acc := testutil.Accumulator{}
+
+	d.Add(counter("a", map[string]interface{}{"bytes": int64(100)}, 0))
+	d.Push(&acc)
+	d.Reset()
+	require.Empty(t, acc.Metrics)
+
+	// The rate is computed with the sample of the previous period.
+	d.Add(counter("a", map[string]interface{}{"bytes": int64(200)}, 10))
+	d.Push(&acc)
+	d.Reset()
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{
+		counter("a", map[string]interface{}{"bytes_rate": 10.0}, 10),
+	}, acc.GetTelegrafMetrics())
+
+	// One period without samples is rolled over.
+	acc.ClearMetrics()
+	d.Push(&acc)
+	d.Reset()
+	d.Add(counter("a", map[string]interface{}{"bytes": int64(500)}, 40))
+	d.Push(&acc)
+	d.Reset()
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{
+		counter("a", map[string]interface{}{"bytes_rate": 10.0}, 40),
+	}, acc.GetTelegrafMetrics())
+
+	// After two periods without samples the series is dropped.
+	acc.ClearMetrics()
+	d.Reset()
+	d.Reset()
+	d.Add(counter("a", map[string]interface{}{"bytes": int64(900)}, 80))
+	d.Push(&acc)
+	require.Empty(t, acc.Metrics)
+}
+
+func TestNoRollOver(t *testing.T) {
+	d := newDerivative()
+	d.MaxRollOver = 0
+	require.NoError(t, d.Init())
+
+	acc := testutil.Accumulator{}
+	d.Add(counter("a", map[string]interface{}{"bytes": int64(100)}, 0))
+	d.Reset()
+	d.Add(counter("a", map[string]interface{}{"bytes": int64(200)}, 10))
+	d.Push(&acc)
+	require.Empty(t, acc.Metrics)
+}
+
+func TestSample(t *testing.T) {
+	d := newDerivative()
+	d.Mode = modeSample
+	d.Suffix = "_per_second"
+	require.NoError(t, d.Init())
+
+	d.Add(counter("a", map[string]interface{}{"rx": int64(100), "tx": int64(0)}, 0))
+	d.Add(counter("a", map[string]interface{}{"rx": int64(300), "tx": int64(10)}, 10))
+	d.Add(counter("a", map[string]interface{}{"rx": int64(400)}, 20))
+	d.Add(counter("a", map[string]interface{}{"rx": int64(400), "tx": int64(40)}, 30))
+
+	acc := testutil.Accumulator{}
+	d.Push(&acc)
+
+	expected := []telegraf.Metric{
+		counter("a", map[string]interface{}{"rx_per_second": 20.0, "tx_per_second": 1.0}, 10),
+		counter("a", map[string]interface{}{"rx_per_second": 10.0}, 20),
+		counter("a", map[string]interface{}{"rx_per_second": 0.0, "tx_per_second": 1.5}, 30),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestCounterWrap(t *testing.T) {
+	tests := []struct {
+		name     string
+		bits     int
+		prev     interface{}
+		cur      interface{}
+		expected float64
+		ok       bool
+	}{
+		{
+			name:     "32 bit wrap",
+			bits:     32,
+			prev:     uint64(math.MaxUint32 - 9),
+			cur:      uint64(10),
+			expected: 20,
+			ok:       true,
+		},
+		{
+			name:     "64 bit wrap",
+			bits:     64,
+			prev:     uint64(math.MaxUint64 - 4),
+			cur:      uint64(5),
+			expected: 10,
+			ok:       true,
+		},
+		{
+			name: "32 bit reset",
+			bits: 32,
+			prev: int64(1000),
+			cur:  int64(10),
+		},
+		{
+			name: "value exceeding 32 bit",
+			bits: 32,
+			prev: uint64(math.MaxUint32 + 10),
+			cur:  uint64(10),
+		},
+		{
+			name: "64 bit reset",
+			bits: 64,
+			prev: uint64(math.MaxUint32 - 9),
+			cur:  uint64(10),
+		},
+		{
+			name: "no wrap",
+			bits: 0,
+			prev: uint64(math.MaxUint64 - 4),
+			cur:  uint64(5),
+		},
+		{
+			name: "float decrease",
+			bits: 64,
+			prev: 10.0,
+			cur:  5.0,
+		},
+		{
+			name:     "gauge",
+			bits:     -1,
+			prev:     int64(10),
+			cur:      int64(5),
+			expected: -5,
+			ok:       true,
+		},
+		{
+			name:     "increase",
+			bits:     64,
+			prev:     uint64(math.MaxUint64 - 4),
+			cur:      uint64(math.MaxUint64),
+			expected: 4,
+			ok:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDerivative()
+			d.CounterBits = tt.bits
+			require.NoError(t, d.Init())
+
+			prev, ok := newSample(tt.prev, time.Unix(0, 0))
+			require.True(t, ok)
+			cur, ok := newSample(tt.cur, time.Unix(1, 0))
+			require.True(t, ok)
+
+			delta, ok := d.delta(prev, cur)
+			require.Equal(t, tt.ok, ok)
+			if tt.ok {
+				require.Equal(t, tt.expected, delta)
+			}
+		})
+	}
+}
+
+func TestCounterReset(t *testing.T) {
+	d := newDerivative()
+	require.NoError(t, d.Init())
+
+	d.Add(counter("a", map[string]interface{}{"bytes": int64(1000)}, 0))
+	d.Add(counter("a", map[string]interface{}{"bytes": int64(2000)}, 10))
+	d.Add(counter("a", map[string]interface{}{"bytes": int64(10)}, 20))
+	d.Add(counter("a", map[string]interface{}{"bytes": int64(410)}, 30))
+
+	acc := testutil.Accumulator{}
+	d.Push(&acc)
+
+	// The interval with the reset is left out: (1000 + 400) / 20s
+	expected := []telegraf.Metric{
+		counter("a", map[string]interface{}{"bytes_rate": 70.0}, 30),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestInitError(t *testing.T) {
+	d := newDerivative()
+	d.Mode = "average"
+	require.Error(t, d.Init())
+
+	d = newDerivative()
+	d.CounterBits = 16
+	require.Error(t, d.Init())
+
+	d = newDerivative()
+	d.MaxRollOver = -1
+	require.Error(t, d.Init())
+}
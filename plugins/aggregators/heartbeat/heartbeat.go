@@ -0,0 +1,171 @@
+package heartbeat
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/metric"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/aggregators"
+)
+
+const (
+	modeMissing = "missing"
+	modeStale   = "stale"
+)
+
+var sampleConfig = `
+  ## General Aggregator Arguments:
+  ## The period on which to flush & clear the aggregator.
+  period = "30s"
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = false
+
+  ## Number of consecutive periods a series must be missing to be reported.
+  # missing_periods = 3
+
+  ## Report missing series by:
+  ##   missing - emitting a metric with the tags of the series
+  ##   stale   - emitting the last metric of the series with a stale field
+  # mode = "missing"
+
+  ## Name of the metric emitted in "missing" mode.
+  # metric_name = "series_missing"
+
+  ## Name of the boolean field added in "stale" mode.
+  # stale_field = "stale"
+
+  ## If false, a missing series is only reported once until it appears
+  ## again.
+  # repeat = true
+
+  ## Number of periods after which missing series are no longer tracked.
+  ## Set to 0 to track series until Telegraf is restarted.
+  # expire_periods = 0
+`
+
+type Heartbeat struct {
+	MissingPeriods int    `toml:"missing_periods"`
+	Mode           string `toml:"mode"`
+	MetricName     string `toml:"metric_name"`
+	StaleField     string `toml:"stale_field"`
+	Repeat         bool   `toml:"repeat"`
+	ExpirePeriods  int    `toml:"expire_periods"`
+
+	cache map[uint64]*series
+}
+
+type series struct {
+	name     string
+	tags     map[string]string
+	last     telegraf.Metric
+	lastSeen time.Time
+	seen     bool
+	missing  int
+}
+
+func (h *Heartbeat) SampleConfig() string {
+	return sampleConfig
+}
+
+func (h *Heartbeat) Description() string {
+	return "Report series that stopped reporting for a number of periods."
+}
+
+func (h *Heartbeat) Init() error {
+	if h.MissingPeriods < 1 {
+		return fmt.Errorf("missing_periods must be at least 1")
+	}
+	switch h.Mode {
+	case "":
+		h.Mode = modeMissing
+	case modeMissing, modeStale:
+	default:
+		return fmt.Errorf("invalid mode %q", h.Mode)
+	}
+	if h.ExpirePeriods < 0 {
+		return fmt.Errorf("expire_periods must not be negative")
+	}
+	if h.ExpirePeriods > 0 && h.ExpirePeriods < h.MissingPeriods {
+		return fmt.Errorf("expire_periods must not be less than missing_periods")
+	}
+	h.cache = make(map[uint64]*series)
+	return nil
+}
+
+func (h *Heartbeat) Add(in telegraf.Metric) {
+	id := in.HashID()
+	s, ok := h.cache[id]
+	if !ok {
+		s = &series{
+			name: in.Name(),
+			tags: in.Tags(),
+		}
+		h.cache[id] = s
+	}
+	s.seen = true
+	if in.Time().After(s.lastSeen) {
+		s.lastSeen = in.Time()
+		if h.Mode == modeStale {
+			s.last = in
+		}
+	}
+}
+
+func (h *Heartbeat) Push(acc telegraf.Accumulator) {
+	now := time.Now()
+	for id, s := range h.cache {
+		if s.seen {
+			s.missing = 0
+			continue
+		}
+		s.missing++
+
+		if h.ExpirePeriods > 0 && s.missing > h.ExpirePeriods {
+			delete(h.cache, id)
+			continue
+		}
+		if s.missing < h.MissingPeriods || (!h.Repeat && s.missing > h.MissingPeriods) {
+			continue
+		}
+
+		switch h.Mode {
+		case modeMissing:
+			tags := make(map[string]string, len(s.tags)+1)
+			for k, v := range s.tags {
+				tags[k] = v
+			}
+			tags["measurement"] = s.name
+			fields := map[string]interface{}{
+				"missing_periods": s.missing,
+				"last_seen":       s.lastSeen.UnixNano(),
+			}
+			acc.AddFields(h.MetricName, fields, tags, now)
+		case modeStale:
+			m, err := metric.New(s.last.Name(), s.last.Tags(), s.last.Fields(), now, s.last.Type())
+			if err != nil {
+				continue
+			}
+			m.AddField(h.StaleField, true)
+			acc.AddMetric(m)
+		}
+	}
+}
+
+func (h *Heartbeat) Reset() {
+	for _, s := range h.cache {
+		s.seen = false
+	}
+}
+
+func init() {
+	aggregators.Add("heartbeat", func() telegraf.Aggregator {
+		return &Heartbeat{
+			MissingPeriods: 3,
+			MetricName:     "series_missing",
+			StaleField:     "stale",
+			Repeat:         true,
+		}
+	})
+}
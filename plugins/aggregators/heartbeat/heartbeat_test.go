@@ -0,0 +1,143 @@
+package heartbeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func disk(path string, sec int64) telegraf.Metric {
+	return testutil.MustMetric("disk",
+		map[string]string{"path": path},
+		map[string]interface{}{"used": int64(42)},
+		time.Unix(sec, 0))
+}
+
+func newHeartbeat() *Heartbeat {
+	return &Heartbeat{
+		MissingPeriods: 2,
+		MetricName:     "series_missing",
+		StaleField:     "stale",
+		Repeat:         true,
+	}
+}
+
+// period adds the metrics and returns the metrics pushed at the end of the
+// period.
+func period(h *Heartbeat, metrics ...telegraf.Metric) []telegraf.Metric {
+	for _, m := range metrics {
+		h.Add(m)
+	}
+	acc := testutil.Accumulator{}
+	h.Push(&acc)
+	h.Reset()
+	return acc.GetTelegrafMetrics()
+}
+
+func missing(path string, periods int, lastSeen int64) telegraf.Metric {
+	return testutil.MustMetric("series_missing",
+		map[string]string{"path": path, "measurement": "disk"},
+		map[string]interface{}{
+			"missing_periods": periods,
+			"last_seen":       time.Unix(lastSeen, 0).UnixNano(),
+		},
+		time.Unix(0, 0))
+}
+
+func TestMissing(t *testing.T) {
+	h := newHeartbeat()
+	require.NoError(t, h.Init())
+
+	require.Empty(t, period(h, disk("/", 0), disk("/data", 0)))
+	require.Empty(t, period(h, disk("/", 10)))
+
+	testutil.RequireMetricsEqual(t,
+		[]telegraf.Metric{missing("/data", 2, 0)},
+		period(h, disk("/", 20)),
+		testutil.IgnoreTime())
+
+	testutil.RequireMetricsEqual(t,
+		[]telegraf.Metric{missing("/data", 3, 0)},
+		period(h),
+		testutil.IgnoreTime())
+
+	// Series reporting again are no longer missing.
+	testutil.RequireMetricsEqual(t,
+		[]telegraf.Metric{missing("/", 2, 20)},
+		period(h, disk("/data", 40)),
+		testutil.IgnoreTime())
+	require.Empty(t, period(h, disk("/", 50), disk("/data", 50)))
+}
+
+func TestNoRepeat(t *testing.T) {
+	h := newHeartbeat()
+	h.MissingPeriods = 1
+	h.Repeat = false
+	require.NoError(t, h.Init())
+
+	require.Empty(t, period(h, disk("/", 0)))
+	testutil.RequireMetricsEqual(t,
+		[]telegraf.Metric{missing("/", 1, 0)},
+		period(h),
+		testutil.IgnoreTime())
+	require.Empty(t, period(h))
+	require.Empty(t, period(h, disk("/", 30)))
+	testutil.RequireMetricsEqual(t,
+		[]telegraf.Metric{missing("/", 1, 30)},
+		period(h),
+		testutil.IgnoreTime())
+}
+
+func TestStale(t *testing.T) {
+	h := newHeartbeat()
+	h.Mode = modeStale
+	require.NoError(t, h.Init())
+
+	last := testutil.MustMetric("disk",
+		map[string]string{"path": "/"},
+		map[string]interface{}{"used": int64(43)},
+		time.Unix(10, 0))
+	require.Empty(t, period(h, last, disk("/", 0)))
+	require.Empty(t, period(h))
+
+	before := time.Now()
+	actual := period(h)
+	expected := []telegraf.Metric{
+		testutil.MustMetric("disk",
+			map[string]string{"path": "/"},
+			map[string]interface{}{"used": int64(43), "stale": true},
+			time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+	require.False(t, actual[0].Time().Before(before))
+}
+
+func TestExpire(t *testing.T) {
+	h := newHeartbeat()
+	h.ExpirePeriods = 3
+	require.NoError(t, h.Init())
+
+	require.Empty(t, period(h, disk("/", 0)))
+	require.Empty(t, period(h))
+	require.Len(t, period(h), 1)
+	require.Len(t, period(h), 1)
+	require.Empty(t, period(h))
+	require.Empty(t, h.cache)
+}
+
+func TestInitError(t *testing.T) {
+	h := newHeartbeat()
+	h.MissingPeriods = 0
+	require.Error(t, h.Init())
+
+	h = newHeartbeat()
+	h.Mode = "absent"
+	require.Error(t, h.Init())
+
+	h = newHeartbeat()
+	h.ExpirePeriods = 1
+	require.Error(t, h.Init())
+}
@@ -0,0 +1,97 @@
+package quantile
+
+import (
+	"math"
+	"sort"
+
+	"github.com/caio/go-tdigest"
+)
+
+type algorithm interface {
+	Add(value float64) error
+	Quantile(q float64) float64
+}
+
+type newAlgorithmFunc func() (algorithm, error)
+
+// tDigest estimates the quantiles with a t-digest sketch, the memory used
+// depends on the compression only.
+type tDigest struct {
+	*tdigest.TDigest
+}
+
+func newTDigest(compression float64) (algorithm, error) {
+	digest, err := tdigest.New(tdigest.Compression(uint32(compression)))
+	if err != nil {
+		return nil, err
+	}
+	return &tDigest{digest}, nil
+}
+
+// exact keeps all samples to compute the exact quantiles.  Once more than
+// maxSamples are added the samples are moved to a t-digest to bound the
+// memory use.
+type exact struct {
+	samples     []float64
+	sorted      bool
+	maxSamples  int
+	compression float64
+	digest      algorithm
+}
+
+func newExact(maxSamples int, compression float64) *exact {
+	return &exact{
+		samples:     make([]float64, 0, 16),
+		maxSamples:  maxSamples,
+		compression: compression,
+	}
+}
+
+func (e *exact) Add(value float64) error {
+	if e.digest != nil {
+		return e.digest.Add(value)
+	}
+
+	if len(e.samples) < e.maxSamples {
+		e.samples = append(e.samples, value)
+		e.sorted = false
+		return nil
+	}
+
+	digest, err := newTDigest(e.compression)
+	if err != nil {
+		return err
+	}
+	for _, v := range e.samples {
+		if err := digest.Add(v); err != nil {
+			return err
+		}
+	}
+	e.digest = digest
+	e.samples = nil
+	return e.digest.Add(value)
+}
+
+// Quantile returns the quantile using linear interpolation between the
+// closest ranks, which is the R7 method used by default in R and NumPy.
+func (e *exact) Quantile(q float64) float64 {
+	if e.digest != nil {
+		return e.digest.Quantile(q)
+	}
+
+	n := len(e.samples)
+	if n == 0 {
+		return math.NaN()
+	}
+	if !e.sorted {
+		sort.Float64s(e.samples)
+		e.sorted = true
+	}
+
+	h := float64(n-1) * q
+	lower := int(math.Floor(h))
+	if lower >= n-1 {
+		return e.samples[n-1]
+	}
+	return e.samples[lower] + (h-float64(lower))*(e.samples[lower+1]-e.samples[lower])
+}
@@ -0,0 +1,197 @@
+package quantile
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/aggregators"
+)
+
+const (
+	algorithmTDigest = "t-digest"
+	algorithmExact   = "exact"
+)
+
+type Quantile struct {
+	Quantiles   []float64       `toml:"quantiles"`
+	Algorithm   string          `toml:"algorithm"`
+	Compression float64         `toml:"compression"`
+	MaxSamples  int             `toml:"max_samples"`
+	Log         telegraf.Logger `toml:"-"`
+
+	cache        map[uint64]aggregate
+	suffixes     []string
+	newAlgorithm newAlgorithmFunc
+}
+
+type aggregate struct {
+	name   string
+	tags   map[string]string
+	fields map[string]algorithm
+}
+
+var sampleConfig = `
+  ## General Aggregator Arguments:
+  ## The period on which to flush & clear the aggregator.
+  period = "30s"
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = false
+
+  ## Quantiles to output in the range [0,1].  The fields are named after the
+  ## quantile in percent, e.g. "usage_p99" or "usage_p99_9" for 0.999.
+  # quantiles = [0.5, 0.9, 0.99]
+
+  ## Algorithm used to compute the quantiles:
+  ##   t-digest - estimate the quantiles using a t-digest sketch
+  ##   exact    - keep the samples and compute the exact quantiles; when
+  ##              exceeding max_samples per field and period, continue with a
+  ##              t-digest
+  # algorithm = "t-digest"
+
+  ## Compression of the t-digest, higher values are more accurate and use
+  ## more memory.  Must be at least 1.
+  # compression = 100.0
+
+  ## Maximum number of samples to keep per field when using the "exact"
+  ## algorithm.
+  # max_samples = 1000
+`
+
+func (q *Quantile) SampleConfig() string {
+	return sampleConfig
+}
+
+func (q *Quantile) Description() string {
+	return "Keep the aggregate quantiles of each metric passing through."
+}
+
+func (q *Quantile) Init() error {
+	if len(q.Quantiles) == 0 {
+		return fmt.Errorf("no quantiles configured")
+	}
+	// The t-digest takes the compression as an integer
+	if q.Compression < 1 || q.Compression > math.MaxUint32 {
+		return fmt.Errorf("compression must be between 1 and %d", uint32(math.MaxUint32))
+	}
+
+	switch q.Algorithm {
+	case "", algorithmTDigest:
+		q.newAlgorithm = func() (algorithm, error) {
+			return newTDigest(q.Compression)
+		}
+	case algorithmExact:
+		if q.MaxSamples <= 0 {
+			return fmt.Errorf("max_samples must be positive")
+		}
+		q.newAlgorithm = func() (algorithm, error) {
+			return newExact(q.MaxSamples, q.Compression), nil
+		}
+	default:
+		return fmt.Errorf("unknown algorithm %q", q.Algorithm)
+	}
+
+	q.suffixes = make([]string, 0, len(q.Quantiles))
+	seen := make(map[string]bool, len(q.Quantiles))
+	for _, quantile := range q.Quantiles {
+		if quantile < 0 || quantile > 1 {
+			return fmt.Errorf("quantile %v out of range [0,1]", quantile)
+		}
+		suffix := fieldSuffix(quantile)
+		if seen[suffix] {
+			return fmt.Errorf("duplicate quantile %v", quantile)
+		}
+		seen[suffix] = true
+		q.suffixes = append(q.suffixes, suffix)
+	}
+
+	q.Reset()
+	return nil
+}
+
+func (q *Quantile) Add(in telegraf.Metric) {
+	id := in.HashID()
+	a, ok := q.cache[id]
+	if !ok {
+		a = aggregate{
+			name:   in.Name(),
+			tags:   in.Tags(),
+			fields: make(map[string]algorithm),
+		}
+		q.cache[id] = a
+	}
+
+	for _, field := range in.FieldList() {
+		fv, ok := convert(field.Value)
+		if !ok || math.IsNaN(fv) || math.IsInf(fv, 0) {
+			continue
+		}
+
+		alg, ok := a.fields[field.Key]
+		if !ok {
+			var err error
+			alg, err = q.newAlgorithm()
+			if err != nil {
+				q.Log.Errorf("Creating quantile estimator for %q failed: %v", field.Key, err)
+				continue
+			}
+			a.fields[field.Key] = alg
+		}
+
+		if err := alg.Add(fv); err != nil {
+			q.Log.Errorf("Adding value of %q failed: %v", field.Key, err)
+		}
+	}
+}
+
+func (q *Quantile) Push(acc telegraf.Accumulator) {
+	for _, a := range q.cache {
+		fields := make(map[string]interface{}, len(a.fields)*len(q.Quantiles))
+		for k, alg := range a.fields {
+			for i, quantile := range q.Quantiles {
+				fields[k+q.suffixes[i]] = alg.Quantile(quantile)
+			}
+		}
+		if len(fields) > 0 {
+			acc.AddFields(a.name, fields, a.tags)
+		}
+	}
+}
+
+func (q *Quantile) Reset() {
+	q.cache = make(map[uint64]aggregate)
+}
+
+// fieldSuffix returns the suffix for the quantile in percent, using "_" as
+// decimal separator.
+func fieldSuffix(quantile float64) string {
+	percent := math.Round(quantile*1e6) / 1e4
+	return "_p" + strings.Replace(strconv.FormatFloat(percent, 'f', -1, 64), ".", "_", 1)
+}
+
+func convert(in interface{}) (float64, bool) {
+	switch v := in.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func init() {
+	aggregators.Add("quantile", func() telegraf.Aggregator {
+		return &Quantile{
+			Quantiles:   []float64{0.5, 0.9, 0.99},
+			Algorithm:   algorithmTDigest,
+			Compression: 100,
+			MaxSamples:  1000,
+		}
+	})
+}
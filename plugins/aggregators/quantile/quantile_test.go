@@ -0,0 +1,150 @@
+package quantile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newQuantile() *Quantile {
+	return &Quantile{
+		Quantiles:   []float64{0.5, 0.9, 0.99},
+		Algorithm:   algorithmTDigest,
+		Compression: 100,
+		MaxSamples:  1000,
+		Log:         testutil.Logger{},
+	}
+}
+
+func addSeries(q *Quantile, n int) {
+	for i := 1; i <= n; i++ {
+		q.Add(testutil.MustMetric("cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"usage": float64(i), "cores": int64(4), "state": "ok"},
+			time.Unix(int64(i), 0)))
+	}
+}
+
+func TestExact(t *testing.T) {
+	q := newQuantile()
+	q.Algorithm = algorithmExact
+	require.NoError(t, q.Init())
+
+	addSeries(q, 100)
+	q.Add(testutil.MustMetric("cpu",
+		map[string]string{"host": "b"},
+		map[string]interface{}{"usage": uint64(7)},
+		time.Unix(0, 0)))
+
+	acc := testutil.Accumulator{}
+	q.Push(&acc)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{
+				"usage_p50": 50.5,
+				"usage_p90": 90.1,
+				"usage_p99": 99.01,
+				"cores_p50": 4.0,
+				"cores_p90": 4.0,
+				"cores_p99": 4.0,
+			},
+			time.Unix(0, 0)),
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "b"},
+			map[string]interface{}{
+				"usage_p50": 7.0,
+				"usage_p90": 7.0,
+				"usage_p99": 7.0,
+			},
+			time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(),
+		testutil.SortMetrics(), testutil.IgnoreTime(), cmpopts.EquateApprox(0, 1e-9))
+}
+
+func TestTDigest(t *testing.T) {
+	q := newQuantile()
+	require.NoError(t, q.Init())
+
+	addSeries(q, 10000)
+
+	acc := testutil.Accumulator{}
+	q.Push(&acc)
+	require.Len(t, acc.Metrics, 1)
+
+	fields := acc.Metrics[0].Fields
+	require.InDelta(t, 5000, fields["usage_p50"], 50)
+	require.InDelta(t, 9000, fields["usage_p90"], 50)
+	require.InDelta(t, 9900, fields["usage_p99"], 20)
+	require.Equal(t, 4.0, fields["cores_p99"])
+}
+
+func TestExactCap(t *testing.T) {
+	q := newQuantile()
+	q.Algorithm = algorithmExact
+	q.MaxSamples = 100
+	require.NoError(t, q.Init())
+
+	addSeries(q, 10000)
+
+	acc := testutil.Accumulator{}
+	q.Push(&acc)
+	require.Len(t, acc.Metrics, 1)
+
+	fields := acc.Metrics[0].Fields
+	require.InDelta(t, 5000, fields["usage_p50"], 50)
+	require.InDelta(t, 9900, fields["usage_p99"], 20)
+
+	for _, a := range q.cache {
+		require.IsType(t, &tDigest{}, a.fields["usage"].(*exact).digest)
+	}
+}
+
+func TestReset(t *testing.T) {
+	q := newQuantile()
+	require.NoError(t, q.Init())
+
+	addSeries(q, 10)
+	q.Reset()
+
+	acc := testutil.Accumulator{}
+	q.Push(&acc)
+	require.Empty(t, acc.Metrics)
+}
+
+func TestFieldSuffix(t *testing.T) {
+	require.Equal(t, "_p0", fieldSuffix(0))
+	require.Equal(t, "_p7", fieldSuffix(0.07))
+	require.Equal(t, "_p50", fieldSuffix(0.5))
+	require.Equal(t, "_p99_9", fieldSuffix(0.999))
+	require.Equal(t, "_p100", fieldSuffix(1))
+}
+
+func TestInitError(t *testing.T) {
+	q := newQuantile()
+	q.Algorithm = "median of medians"
+	require.Error(t, q.Init())
+
+	q = newQuantile()
+	q.Quantiles = []float64{0.5, 1.5}
+	require.Error(t, q.Init())
+
+	q = newQuantile()
+	q.Quantiles = []float64{0.5, 0.50000001}
+	require.Error(t, q.Init())
+
+	q = newQuantile()
+	q.Algorithm = algorithmExact
+	q.MaxSamples = 0
+	require.Error(t, q.Init())
+
+	q = newQuantile()
+	q.Compression = 0.5
+	require.Error(t, q.Init())
+}
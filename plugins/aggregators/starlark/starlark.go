@@ -0,0 +1,131 @@
+package starlark
+
+import (
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/aggregators"
+	common "github.com/shanas-swi/telegraf-v1.16.3/plugins/common/starlark"
+	"go.starlark.net/starlark"
+)
+
+const (
+	description  = "Aggregate metrics using a Starlark script"
+	sampleConfig = `
+  ## General Aggregator Arguments:
+  ## The period on which to flush & clear the aggregator.
+  period = "30s"
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = false
+
+  ## The Starlark source can be set as a string in this configuration file, or
+  ## by referencing a file containing the script.  Only one source or script
+  ## should be set at once.
+  ##
+  ## Source of the Starlark script.
+  source = '''
+def add(metric):
+  state["last"] = deepcopy(metric)
+
+def push():
+  return state.get("last")
+
+def reset():
+  state.clear()
+'''
+
+  ## File containing a Starlark script.
+  # script = "/usr/local/bin/myscript.star"
+`
+)
+
+type Starlark struct {
+	common.StarlarkCommon
+
+	addFunc   *starlark.Function
+	pushFunc  *starlark.Function
+	resetFunc *starlark.Function
+}
+
+func (s *Starlark) Init() error {
+	err := s.StarlarkCommon.Init()
+	if err != nil {
+		return err
+	}
+
+	// The source should define the add, push and reset functions.
+	s.addFunc, err = s.Function("add", 1)
+	if err != nil {
+		return err
+	}
+	s.pushFunc, err = s.Function("push", 0)
+	if err != nil {
+		return err
+	}
+	s.resetFunc, err = s.Function("reset", 0)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *Starlark) SampleConfig() string {
+	return sampleConfig
+}
+
+func (s *Starlark) Description() string {
+	return description
+}
+
+func (s *Starlark) Add(metric telegraf.Metric) {
+	// The metric is passed on to the outputs, so the script may only read
+	// it.  Metrics kept in the state have to be copied with deepcopy.
+	wrapper := &common.Metric{}
+	wrapper.Wrap(metric)
+	wrapper.Freeze()
+
+	if _, err := s.Call(s.addFunc, starlark.Tuple{wrapper}); err != nil {
+		s.Log.Errorf("Error calling add: %v", err)
+	}
+}
+
+// Push adds the metrics returned by the push function.  The metrics are
+// copied as the script may still keep them in the state.
+func (s *Starlark) Push(acc telegraf.Accumulator) {
+	rv, err := s.Call(s.pushFunc, nil)
+	if err != nil {
+		s.Log.Errorf("Error calling push: %v", err)
+		return
+	}
+
+	switch rv := rv.(type) {
+	case *starlark.List:
+		iter := rv.Iterate()
+		defer iter.Done()
+		var v starlark.Value
+		for iter.Next(&v) {
+			switch v := v.(type) {
+			case *common.Metric:
+				acc.AddMetric(v.Unwrap().Copy())
+			default:
+				s.Log.Errorf("Invalid type returned in list: %s", v.Type())
+			}
+		}
+	case *common.Metric:
+		acc.AddMetric(rv.Unwrap().Copy())
+	case starlark.NoneType:
+	default:
+		s.Log.Errorf("Invalid type returned: %T", rv)
+	}
+}
+
+func (s *Starlark) Reset() {
+	if _, err := s.Call(s.resetFunc, nil); err != nil {
+		s.Log.Errorf("Error calling reset: %v", err)
+	}
+}
+
+func init() {
+	aggregators.Add("starlark", func() telegraf.Aggregator {
+		return &Starlark{}
+	})
+}
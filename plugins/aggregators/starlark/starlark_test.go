@@ -0,0 +1,240 @@
+package starlark
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	common "github.com/shanas-swi/telegraf-v1.16.3/plugins/common/starlark"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/parsers"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newStarlarkFromSource(source string) *Starlark {
+	return &Starlark{
+		StarlarkCommon: common.StarlarkCommon{
+			Source: source,
+			Log:    testutil.Logger{},
+		},
+	}
+}
+
+func TestInitError(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		err    string
+	}{
+		{
+			name: "add must be defined",
+			source: `
+def push():
+	return None
+def reset():
+	pass
+`,
+			err: "add is not defined",
+		},
+		{
+			name: "push must not take parameters",
+			source: `
+def add(metric):
+	pass
+def push(metric):
+	return None
+def reset():
+	pass
+`,
+			err: "push function must not take any parameters",
+		},
+		{
+			name: "reset must be a function",
+			source: `
+def add(metric):
+	pass
+def push():
+	return None
+reset = 42
+`,
+			err: "reset is not a function",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := newStarlarkFromSource(tt.source)
+			require.EqualError(t, plugin.Init(), tt.err)
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	plugin := newStarlarkFromSource(`
+def add(metric):
+	state["count"] = state.get("count", 0) + 1
+	state["sum"] = state.get("sum", 0) + metric.fields["value"]
+
+def push():
+	m = Metric("sum")
+	m.fields["count"] = state["count"]
+	m.fields["sum"] = state["sum"]
+	return [m]
+
+def reset():
+	state.clear()
+`)
+	require.NoError(t, plugin.Init())
+
+	for _, v := range []int64{1, 2, 3} {
+		plugin.Add(testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"value": v},
+			time.Unix(0, 0)))
+	}
+
+	acc := &testutil.Accumulator{}
+	plugin.Push(acc)
+	plugin.Reset()
+	plugin.Add(testutil.MustMetric("cpu",
+		map[string]string{},
+		map[string]interface{}{"value": int64(10)},
+		time.Unix(0, 0)))
+	plugin.Push(acc)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("sum",
+			map[string]string{},
+			map[string]interface{}{"count": 3, "sum": 6},
+			time.Unix(0, 0)),
+		testutil.MustMetric("sum",
+			map[string]string{},
+			map[string]interface{}{"count": 1, "sum": 10},
+			time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestAddMetricIsReadOnly(t *testing.T) {
+	plugin := newStarlarkFromSource(`
+def add(metric):
+	state["error"] = catch(lambda: metric.fields.pop("value"))
+	state["last"] = deepcopy(metric)
+
+def push():
+	m = state["last"]
+	m.fields["error"] = state["error"]
+	return m
+
+def reset():
+	pass
+`)
+	require.NoError(t, plugin.Init())
+
+	m := testutil.MustMetric("cpu",
+		map[string]string{},
+		map[string]interface{}{"value": 42},
+		time.Unix(0, 0))
+	plugin.Add(m)
+	require.Equal(t, map[string]interface{}{"value": int64(42)}, m.Fields())
+
+	acc := &testutil.Accumulator{}
+	plugin.Push(acc)
+	// Pushing the metric kept in the state twice must not share the metric.
+	plugin.Push(acc)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"value": 42, "error": "pop: cannot modify frozen metric"},
+			time.Unix(0, 0)),
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"value": 42, "error": "pop: cannot modify frozen metric"},
+			time.Unix(0, 0)),
+	}
+	metrics := acc.GetTelegrafMetrics()
+	testutil.RequireMetricsEqual(t, expected, metrics)
+	require.False(t, metrics[0] == metrics[1])
+}
+
+func TestAllScriptTestData(t *testing.T) {
+	// can be run from multiple folders
+	paths := []string{"testdata", "plugins/aggregators/starlark/testdata"}
+	for _, testdataPath := range paths {
+		filepath.Walk(testdataPath, func(path string, info os.FileInfo, err error) error {
+			if info == nil {
+				return nil
+			}
+			if info.IsDir() {
+				// Libraries are loaded by the examples and not scripts on
+				// their own.
+				if info.Name() == "lib" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			fn := path
+			t.Run(fn, func(t *testing.T) {
+				b, err := ioutil.ReadFile(fn)
+				require.NoError(t, err)
+				lines := strings.Split(string(b), "\n")
+				inputMetrics := parseMetricsFrom(t, lines, "Example Input:")
+				outputMetrics := parseMetricsFrom(t, lines, "Example Output:")
+
+				plugin := &Starlark{
+					StarlarkCommon: common.StarlarkCommon{
+						Script: fn,
+						Log:    testutil.Logger{},
+					},
+				}
+				require.NoError(t, plugin.Init())
+
+				for _, m := range inputMetrics {
+					plugin.Add(m)
+				}
+
+				acc := &testutil.Accumulator{}
+				plugin.Push(acc)
+				plugin.Reset()
+
+				testutil.RequireMetricsEqual(t, outputMetrics, acc.GetTelegrafMetrics(), testutil.SortMetrics(), testutil.IgnoreTime())
+			})
+			return nil
+		})
+	}
+}
+
+var parser, _ = parsers.NewInfluxParser() // literally never returns errors.
+
+// parses metric lines out of line protocol following a header, with a trailing blank line
+func parseMetricsFrom(t *testing.T, lines []string, header string) (metrics []telegraf.Metric) {
+	require.NotZero(t, len(lines), "Expected some lines to parse from .star file, found none")
+	startIdx := -1
+	endIdx := len(lines)
+	for i := range lines {
+		if strings.TrimLeft(lines[i], "# ") == header {
+			startIdx = i + 1
+			break
+		}
+	}
+	require.NotEqual(t, -1, startIdx, fmt.Sprintf("Header %q must exist in file", header))
+	for i := startIdx; i < len(lines); i++ {
+		line := strings.TrimLeft(lines[i], "# ")
+		if line == "" {
+			endIdx = i
+			break
+		}
+	}
+	for i := startIdx; i < endIdx; i++ {
+		m, err := parser.ParseLine(strings.TrimLeft(lines[i], "# "))
+		require.NoError(t, err, fmt.Sprintf("Expected to be able to parse %q metric, but found error", header))
+		metrics = append(metrics, m)
+	}
+	return metrics
+}
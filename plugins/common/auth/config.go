@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// HTTPClientConfig represents the standard client authentication config of HTTP
+// based plugins.
+type HTTPClientConfig struct {
+	// OAuth2 client credentials grant
+	ClientID     string   `toml:"client_id"`
+	ClientSecret string   `toml:"client_secret"`
+	TokenURL     string   `toml:"token_url"`
+	Scopes       []string `toml:"scopes"`
+
+	// File containing a bearer token, re-read when it changes
+	BearerTokenFile string `toml:"bearer_token_file"`
+
+	// Headers with their value read from a file, re-read when it changes
+	HeaderFiles map[string]string `toml:"header_files"`
+}
+
+// HeaderProvider sets authentication headers on requests.
+type HeaderProvider interface {
+	SetHeaders(req *http.Request) error
+}
+
+// Providers returns the header providers for the configured authentication
+// methods.  The given client is used to request OAuth2 tokens; if nil the
+// default client is used.
+func (c *HTTPClientConfig) Providers(client *http.Client) ([]HeaderProvider, error) {
+	var providers []HeaderProvider
+
+	if c.ClientID != "" || c.ClientSecret != "" || c.TokenURL != "" {
+		if c.ClientID == "" || c.ClientSecret == "" || c.TokenURL == "" {
+			return nil, errors.New("client_id, client_secret and token_url are required for OAuth2")
+		}
+		providers = append(providers, NewOAuth2(clientcredentials.Config{
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			TokenURL:     c.TokenURL,
+			Scopes:       c.Scopes,
+		}, client))
+	}
+
+	if c.BearerTokenFile != "" {
+		providers = append(providers, NewFileHeader("Authorization", "Bearer ", c.BearerTokenFile))
+	}
+
+	// Sort the headers for a deterministic order of the providers
+	headers := make([]string, 0, len(c.HeaderFiles))
+	for header := range c.HeaderFiles {
+		headers = append(headers, header)
+	}
+	sort.Strings(headers)
+	for _, header := range headers {
+		providers = append(providers, NewFileHeader(header, "", c.HeaderFiles[header]))
+	}
+
+	return providers, nil
+}
+
+// Transport returns a http.RoundTripper adding the configured authentication
+// to the requests sent using base.  The base transport is also used to
+// request OAuth2 tokens.  If no authentication is configured, base is
+// returned.
+func (c *HTTPClientConfig) Transport(base http.RoundTripper) (http.RoundTripper, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	providers, err := c.Providers(&http.Client{Transport: base, Timeout: 30 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	if len(providers) == 0 {
+		return base, nil
+	}
+	return &Transport{Base: base, Providers: providers}, nil
+}
+
+// Transport is a http.RoundTripper setting the headers of all providers on
+// the requests before sending them using the base transport.
+type Transport struct {
+	Base      http.RoundTripper
+	Providers []HeaderProvider
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the request
+	req = req.Clone(req.Context())
+	for _, provider := range t.Providers {
+		if err := provider.SetHeaders(req); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, err
+		}
+	}
+	return t.Base.RoundTrip(req)
+}
+
+// OAuth2 sets the bearer token obtained using the OAuth2 client credentials
+// grant.  Tokens are cached until they expire.
+type OAuth2 struct {
+	source oauth2.TokenSource
+}
+
+// NewOAuth2 returns a provider requesting tokens using the given client.
+func NewOAuth2(config clientcredentials.Config, client *http.Client) *OAuth2 {
+	ctx := context.Background()
+	if client != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, client)
+	}
+	return &OAuth2{source: config.TokenSource(ctx)}
+}
+
+func (o *OAuth2) SetHeaders(req *http.Request) error {
+	token, err := o.source.Token()
+	if err != nil {
+		return fmt.Errorf("requesting OAuth2 token failed: %v", err)
+	}
+	token.SetAuthHeader(req)
+	return nil
+}
+
+// FileHeader sets a header to the content of a file.  The file is re-read
+// whenever its modification time or size changes, so rotated credentials
+// such as Kubernetes service account tokens are picked up.
+type FileHeader struct {
+	Header string
+	Prefix string
+	Path   string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	value   string
+}
+
+// NewFileHeader returns a provider setting the header to the content of the
+// file at path with the given prefix.
+func NewFileHeader(header, prefix, path string) *FileHeader {
+	return &FileHeader{Header: header, Prefix: prefix, Path: path}
+}
+
+func (f *FileHeader) SetHeaders(req *http.Request) error {
+	value, err := f.read()
+	if err != nil {
+		return err
+	}
+	req.Header.Set(f.Header, f.Prefix+value)
+	return nil
+}
+
+func (f *FileHeader) read() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return "", err
+	}
+	if f.value != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.value, nil
+	}
+
+	content, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		return "", err
+	}
+	value := strings.TrimSpace(string(content))
+	if value == "" {
+		return "", fmt.Errorf("file %q is empty", f.Path)
+	}
+
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	f.value = value
+	return value, nil
+}
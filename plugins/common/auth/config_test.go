@@ -0,0 +1,119 @@
+package auth_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportNoAuth(t *testing.T) {
+	var cfg auth.HTTPClientConfig
+	base := &http.Transport{}
+	transport, err := cfg.Transport(base)
+	require.NoError(t, err)
+	require.Equal(t, base, transport)
+}
+
+func TestOAuth2IncompleteConfig(t *testing.T) {
+	cfg := auth.HTTPClientConfig{ClientID: "telegraf"}
+	_, err := cfg.Transport(nil)
+	require.Error(t, err)
+}
+
+func TestOAuth2(t *testing.T) {
+	var tokenRequests int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenRequests, 1)
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+		require.Equal(t, "read write", r.Form.Get("scope"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token": "token-1", "token_type": "Bearer", "expires_in": 3600}`)
+	}))
+	defer tokenServer.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer token-1", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	cfg := auth.HTTPClientConfig{
+		ClientID:     "telegraf",
+		ClientSecret: "secret",
+		TokenURL:     tokenServer.URL,
+		Scopes:       []string{"read", "write"},
+	}
+	transport, err := cfg.Transport(nil)
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(ts.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	}
+
+	// The token is cached until it expires
+	require.Equal(t, int32(1), atomic.LoadInt32(&tokenRequests))
+}
+
+func TestBearerTokenFileRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tokenFile := filepath.Join(dir, "token")
+	keyFile := filepath.Join(dir, "key")
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("token-1\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte("key-1"), 0600))
+
+	var authorization, key string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		key = r.Header.Get("X-API-Key")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	cfg := auth.HTTPClientConfig{
+		BearerTokenFile: tokenFile,
+		HeaderFiles:     map[string]string{"X-API-Key": keyFile},
+	}
+	transport, err := cfg.Transport(nil)
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+
+	req, err := http.NewRequest("GET", ts.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, "Bearer token-1", authorization)
+	require.Equal(t, "key-1", key)
+	// The request of the caller is not modified
+	require.Empty(t, req.Header.Get("Authorization"))
+
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("token-2\n"), 0600))
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(tokenFile, future, future))
+
+	resp, err = client.Get(ts.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, "Bearer token-2", authorization)
+
+	require.NoError(t, os.Remove(keyFile))
+	_, err = client.Get(ts.URL)
+	require.Error(t, err)
+}
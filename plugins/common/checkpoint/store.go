@@ -0,0 +1,162 @@
+// Package checkpoint persists the read positions of files, so inputs reading
+// files can continue where they stopped after a restart.
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const version = 1
+
+// Identity identifies a file independent of its name, it is used to detect
+// files replaced by log rotation.  On systems without inodes the identity
+// is always empty.
+type Identity struct {
+	Device uint64 `json:"device"`
+	Inode  uint64 `json:"inode"`
+}
+
+// IdentityOf returns the identity of the file described by info.
+func IdentityOf(info os.FileInfo) Identity {
+	return identityOf(info)
+}
+
+// Position is the offset in a file up to which the content was processed.
+type Position struct {
+	Identity
+	Offset int64 `json:"offset"`
+}
+
+type storeFile struct {
+	Version int                 `json:"version"`
+	Files   map[string]Position `json:"files"`
+}
+
+// Store holds the positions of files by their name.  Positions are kept in
+// memory and written to disk when calling Save.  It is safe for concurrent
+// use.
+type Store struct {
+	path string
+
+	mu        sync.Mutex
+	positions map[string]Position
+	dirty     bool
+}
+
+// Open returns the store persisted at path.  If the file does not exist the
+// store is empty.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, positions: map[string]Position{}}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var content storeFile
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("parsing checkpoint file %q failed: %v", path, err)
+	}
+	if content.Version != version {
+		return nil, fmt.Errorf("unsupported version %d of checkpoint file %q", content.Version, path)
+	}
+	for filename, pos := range content.Files {
+		s.positions[filename] = pos
+	}
+	return s, nil
+}
+
+// Get returns the stored position of a file.
+func (s *Store) Get(filename string) (Position, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pos, ok := s.positions[filename]
+	return pos, ok
+}
+
+// Set stores the position of a file.
+func (s *Store) Set(filename string, pos Position) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.positions[filename] != pos {
+		s.positions[filename] = pos
+		s.dirty = true
+	}
+}
+
+// Offset returns the offset at which reading the file should continue.  If
+// the file was replaced or truncated since the position was stored, the
+// file is read from the beginning.  The returned bool is false if no
+// position is stored for the file.
+func (s *Store) Offset(filename string) (int64, bool) {
+	pos, ok := s.Get(filename)
+	if !ok {
+		return 0, false
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return 0, false
+	}
+	if IdentityOf(info) != pos.Identity || info.Size() < pos.Offset {
+		return 0, true
+	}
+	return pos.Offset, true
+}
+
+// Save writes the positions to disk if they changed since the last call.
+// The file is replaced atomically, so it is never left partially written.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	if !s.dirty {
+		s.mu.Unlock()
+		return nil
+	}
+	content := storeFile{Version: version, Files: make(map[string]Position, len(s.positions))}
+	for filename, pos := range s.positions {
+		content.Files[filename] = pos
+	}
+	s.dirty = false
+	s.mu.Unlock()
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(s.path, data); err != nil {
+		s.mu.Lock()
+		s.dirty = true
+		s.mu.Unlock()
+		return fmt.Errorf("writing checkpoint file %q failed: %v", s.path, err)
+	}
+	return nil
+}
+
+func writeFile(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
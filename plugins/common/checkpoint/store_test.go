@@ -0,0 +1,92 @@
+package checkpoint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreSaveAndOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint.json")
+
+	store, err := Open(path)
+	require.NoError(t, err)
+	_, ok := store.Get("/var/log/syslog")
+	require.False(t, ok)
+
+	pos := Position{Identity: Identity{Device: 1, Inode: 2}, Offset: 42}
+	store.Set("/var/log/syslog", pos)
+	require.NoError(t, store.Save())
+
+	store, err = Open(path)
+	require.NoError(t, err)
+	actual, ok := store.Get("/var/log/syslog")
+	require.True(t, ok)
+	require.Equal(t, pos, actual)
+
+	// Only the checkpoint file is left in the directory
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
+
+func TestStoreInvalidFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint.json")
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("{"), 0644))
+	_, err = Open(path)
+	require.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"version": 2, "files": {}}`), 0644))
+	_, err = Open(path)
+	require.Error(t, err)
+}
+
+func TestStoreOffset(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "app.log")
+	require.NoError(t, ioutil.WriteFile(filename, []byte("line 1\nline 2\n"), 0644))
+	info, err := os.Stat(filename)
+	require.NoError(t, err)
+
+	store, err := Open(filepath.Join(dir, "checkpoint.json"))
+	require.NoError(t, err)
+
+	_, ok := store.Offset(filename)
+	require.False(t, ok)
+
+	store.Set(filename, Position{Identity: IdentityOf(info), Offset: 7})
+	offset, ok := store.Offset(filename)
+	require.True(t, ok)
+	require.Equal(t, int64(7), offset)
+
+	// Truncated file
+	require.NoError(t, ioutil.WriteFile(filename, []byte("new\n"), 0644))
+	offset, ok = store.Offset(filename)
+	require.True(t, ok)
+	require.Equal(t, int64(0), offset)
+
+	if runtime.GOOS == "windows" {
+		t.Skip("Files are not identified on windows")
+	}
+
+	// Rotated file
+	require.NoError(t, os.Rename(filename, filename+".1"))
+	require.NoError(t, ioutil.WriteFile(filename, []byte("line 1\nline 2\n"), 0644))
+	offset, ok = store.Offset(filename)
+	require.True(t, ok)
+	require.Equal(t, int64(0), offset)
+}
@@ -0,0 +1,53 @@
+package otlp
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWireFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  proto.Message
+		expected []byte
+	}{
+		{
+			name: "number data point",
+			message: &NumberDataPoint{
+				TimeUnixNano: 1,
+				Value:        &NumberDataPoint_AsInt{AsInt: -1},
+				Attributes: []*KeyValue{
+					{Key: "a", Value: &AnyValue{Value: &AnyValue_StringValue{StringValue: "b"}}},
+				},
+			},
+			expected: []byte{
+				0x19, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x3a, 0x08, 0x0a, 0x01, 'a', 0x12, 0x03, 0x0a, 0x01, 'b',
+				0x31, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+			},
+		},
+		{
+			name: "exponential buckets",
+			message: &ExponentialHistogramDataPoint_Buckets{
+				Offset:       -1,
+				BucketCounts: []uint64{1, 2},
+			},
+			expected: []byte{0x08, 0x01, 0x12, 0x02, 0x01, 0x02},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := proto.Marshal(tt.message)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, buf)
+
+			decoded := proto.Clone(tt.message)
+			decoded.Reset()
+			require.NoError(t, proto.Unmarshal(buf, decoded))
+			require.True(t, proto.Equal(tt.message, decoded))
+		})
+	}
+}
@@ -90,6 +90,10 @@ func (d FieldDict) Get(key starlark.Value) (v starlark.Value, found bool, err er
 // SetKey implements the starlark.HasSetKey interface to support map update
 // using x[k]=v syntax, like a dictionary.
 func (d FieldDict) SetKey(k, v starlark.Value) error {
+	if d.frozen {
+		return errors.New("cannot modify frozen metric")
+	}
+
 	if d.fieldIterCount > 0 {
 		return fmt.Errorf("cannot insert during iteration")
 	}
@@ -124,6 +128,10 @@ func (d FieldDict) Items() []starlark.Tuple {
 }
 
 func (d FieldDict) Clear() error {
+	if d.frozen {
+		return errors.New("cannot modify frozen metric")
+	}
+
 	if d.fieldIterCount > 0 {
 		return fmt.Errorf("cannot delete during iteration")
 	}
@@ -140,6 +148,10 @@ func (d FieldDict) Clear() error {
 }
 
 func (d FieldDict) PopItem() (v starlark.Value, err error) {
+	if d.frozen {
+		return nil, errors.New("cannot modify frozen metric")
+	}
+
 	if d.fieldIterCount > 0 {
 		return nil, fmt.Errorf("cannot delete during iteration")
 	}
@@ -163,6 +175,10 @@ func (d FieldDict) PopItem() (v starlark.Value, err error) {
 }
 
 func (d FieldDict) Delete(k starlark.Value) (v starlark.Value, found bool, err error) {
+	if d.frozen {
+		return nil, false, errors.New("cannot modify frozen metric")
+	}
+
 	if d.fieldIterCount > 0 {
 		return nil, false, fmt.Errorf("cannot delete during iteration")
 	}
@@ -0,0 +1,162 @@
+package starlark
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// MathModule is the math module available using load("math.star", "math").
+// The functions follow the math library of starlark-go, which is not
+// available in the version used by Telegraf.
+var MathModule = &starlarkstruct.Module{
+	Name: "math",
+	Members: starlark.StringDict{
+		"ceil":      starlark.NewBuiltin("ceil", ceil),
+		"copysign":  newBinaryBuiltin("copysign", math.Copysign),
+		"fabs":      newUnaryBuiltin("fabs", math.Abs),
+		"floor":     starlark.NewBuiltin("floor", floor),
+		"mod":       newBinaryBuiltin("mod", math.Mod),
+		"pow":       newBinaryBuiltin("pow", math.Pow),
+		"remainder": newBinaryBuiltin("remainder", math.Remainder),
+		"round":     newUnaryBuiltin("round", math.Round),
+
+		"exp":  newUnaryBuiltin("exp", math.Exp),
+		"sqrt": newUnaryBuiltin("sqrt", math.Sqrt),
+
+		"acos":    newUnaryBuiltin("acos", math.Acos),
+		"asin":    newUnaryBuiltin("asin", math.Asin),
+		"atan":    newUnaryBuiltin("atan", math.Atan),
+		"atan2":   newBinaryBuiltin("atan2", math.Atan2),
+		"cos":     newUnaryBuiltin("cos", math.Cos),
+		"hypot":   newBinaryBuiltin("hypot", math.Hypot),
+		"sin":     newUnaryBuiltin("sin", math.Sin),
+		"tan":     newUnaryBuiltin("tan", math.Tan),
+		"degrees": newUnaryBuiltin("degrees", degrees),
+		"radians": newUnaryBuiltin("radians", radians),
+
+		"acosh": newUnaryBuiltin("acosh", math.Acosh),
+		"asinh": newUnaryBuiltin("asinh", math.Asinh),
+		"atanh": newUnaryBuiltin("atanh", math.Atanh),
+		"cosh":  newUnaryBuiltin("cosh", math.Cosh),
+		"sinh":  newUnaryBuiltin("sinh", math.Sinh),
+		"tanh":  newUnaryBuiltin("tanh", math.Tanh),
+
+		"log":   starlark.NewBuiltin("log", logarithm),
+		"gamma": newUnaryBuiltin("gamma", math.Gamma),
+
+		"isinf": starlark.NewBuiltin("isinf", isinf),
+		"isnan": starlark.NewBuiltin("isnan", isnan),
+
+		"e":   starlark.Float(math.E),
+		"pi":  starlark.Float(math.Pi),
+		"inf": starlark.Float(math.Inf(1)),
+		"nan": starlark.Float(math.NaN()),
+	},
+}
+
+// floatOrInt unpacks an int or float argument as float.
+type floatOrInt float64
+
+func (f *floatOrInt) Unpack(v starlark.Value) error {
+	x, ok := starlark.AsFloat(v)
+	if !ok {
+		return fmt.Errorf("got %s, want float or int", v.Type())
+	}
+	*f = floatOrInt(x)
+	return nil
+}
+
+func newUnaryBuiltin(name string, fn func(float64) float64) *starlark.Builtin {
+	return starlark.NewBuiltin(name, func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var x floatOrInt
+		if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &x); err != nil {
+			return nil, err
+		}
+		return starlark.Float(fn(float64(x))), nil
+	})
+}
+
+func newBinaryBuiltin(name string, fn func(float64, float64) float64) *starlark.Builtin {
+	return starlark.NewBuiltin(name, func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var x, y floatOrInt
+		if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 2, &x, &y); err != nil {
+			return nil, err
+		}
+		return starlark.Float(fn(float64(x), float64(y))), nil
+	})
+}
+
+// ceil returns the ceiling of x as int.
+func ceil(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	return roundToInt(b, args, kwargs, math.Ceil)
+}
+
+// floor returns the floor of x as int.
+func floor(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	return roundToInt(b, args, kwargs, math.Floor)
+}
+
+func roundToInt(b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple, fn func(float64) float64) (starlark.Value, error) {
+	var x starlark.Value
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &x); err != nil {
+		return nil, err
+	}
+
+	switch x := x.(type) {
+	case starlark.Int:
+		return x, nil
+	case starlark.Float:
+		f := fn(float64(x))
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, nameErr(b, "cannot convert "+x.String()+" to int")
+		}
+		if f >= math.MinInt64 && f < math.MaxInt64 {
+			return starlark.MakeInt64(int64(f)), nil
+		}
+		i, _ := big.NewFloat(f).Int(nil)
+		return starlark.MakeBigInt(i), nil
+	}
+	return nil, nameErr(b, fmt.Sprintf("got %s, want float or int", x.Type()))
+}
+
+// logarithm returns the logarithm of x in the given base, or the natural
+// logarithm if the base is omitted.
+func logarithm(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var x, base floatOrInt = 0, math.E
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &x, &base); err != nil {
+		return nil, err
+	}
+	if base == 1 {
+		return nil, nameErr(b, errors.New("division by zero"))
+	}
+	return starlark.Float(math.Log(float64(x)) / math.Log(float64(base))), nil
+}
+
+func isinf(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var x floatOrInt
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &x); err != nil {
+		return nil, err
+	}
+	return starlark.Bool(math.IsInf(float64(x), 0)), nil
+}
+
+func isnan(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var x floatOrInt
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &x); err != nil {
+		return nil, err
+	}
+	return starlark.Bool(math.IsNaN(float64(x))), nil
+}
+
+func degrees(x float64) float64 {
+	return x * 180 / math.Pi
+}
+
+func radians(x float64) float64 {
+	return x * math.Pi / 180
+}
@@ -0,0 +1,198 @@
+package starlark
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"go.starlark.net/resolve"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkjson"
+)
+
+// StarlarkCommon loads and executes a Starlark script, it is shared by the
+// plugins implemented in Starlark.
+type StarlarkCommon struct {
+	Source string `toml:"source"`
+	Script string `toml:"script"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	thread    *starlark.Thread
+	builtins  starlark.StringDict
+	globals   starlark.StringDict
+	libraries map[string]*library
+}
+
+// library is a Starlark file loaded by the script, the entry is nil while
+// the file is being loaded.
+type library struct {
+	globals starlark.StringDict
+	err     error
+}
+
+// Init executes the script and freezes its global scope.
+func (s *StarlarkCommon) Init() error {
+	if s.Source == "" && s.Script == "" {
+		return errors.New("one of source or script must be set")
+	}
+	if s.Source != "" && s.Script != "" {
+		return errors.New("both source or script cannot be set")
+	}
+
+	s.thread = &starlark.Thread{
+		Print: func(_ *starlark.Thread, msg string) { s.Log.Debug(msg) },
+		Load:  s.load,
+	}
+	s.libraries = make(map[string]*library)
+
+	s.builtins = starlark.StringDict{}
+	s.builtins["Metric"] = starlark.NewBuiltin("Metric", newMetric)
+	s.builtins["deepcopy"] = starlark.NewBuiltin("deepcopy", deepcopy)
+	s.builtins["catch"] = starlark.NewBuiltin("catch", catch)
+	// The state dict is shared between all calls of the script functions
+	// and is the only global that can be modified.
+	s.builtins["state"] = starlark.NewDict(0)
+
+	program, err := s.sourceProgram(s.builtins)
+	if err != nil {
+		return err
+	}
+
+	// Execute source
+	s.globals, err = program.Init(s.thread, s.builtins)
+	if err != nil {
+		return err
+	}
+
+	// Freeze the global state.  This prevents modifications to the plugin
+	// state and prevents scripts from containing errors storing tracking
+	// metrics.  Values that need to be kept between calls are stored in the
+	// state dict, which is predeclared and therefore not frozen.
+	s.globals.Freeze()
+	return nil
+}
+
+func (s *StarlarkCommon) sourceProgram(builtins starlark.StringDict) (*starlark.Program, error) {
+	if s.Source != "" {
+		_, program, err := starlark.SourceProgram("source.starlark", s.Source, builtins.Has)
+		return program, err
+	}
+	_, program, err := starlark.SourceProgram(s.Script, nil, builtins.Has)
+	return program, err
+}
+
+// Function returns the function with the given name defined by the script.
+func (s *StarlarkCommon) Function(name string, numParams int) (*starlark.Function, error) {
+	value := s.globals[name]
+	if value == nil {
+		return nil, fmt.Errorf("%s is not defined", name)
+	}
+
+	function, ok := value.(*starlark.Function)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a function", name)
+	}
+
+	if function.NumParams() != numParams {
+		switch numParams {
+		case 0:
+			return nil, fmt.Errorf("%s function must not take any parameters", name)
+		case 1:
+			return nil, fmt.Errorf("%s function must take one parameter", name)
+		default:
+			return nil, fmt.Errorf("%s function must take %d parameters", name, numParams)
+		}
+	}
+	return function, nil
+}
+
+// Call calls the function with the arguments and logs the backtrace of
+// errors.
+func (s *StarlarkCommon) Call(function *starlark.Function, args starlark.Tuple) (starlark.Value, error) {
+	rv, err := starlark.Call(s.thread, function, args, nil)
+	if err != nil {
+		if err, ok := err.(*starlark.EvalError); ok {
+			for _, line := range strings.Split(err.Backtrace(), "\n") {
+				s.Log.Error(line)
+			}
+		}
+		return nil, err
+	}
+	return rv, nil
+}
+
+func (s *StarlarkCommon) load(thread *starlark.Thread, module string) (starlark.StringDict, error) {
+	switch module {
+	case "json.star":
+		return starlark.StringDict{
+			"json": starlarkjson.Module,
+		}, nil
+	case "logging.star":
+		return starlark.StringDict{
+			"log": LogModule(s.Log),
+		}, nil
+	case "math.star":
+		return starlark.StringDict{
+			"math": MathModule,
+		}, nil
+	case "time.star":
+		return starlark.StringDict{
+			"time": TimeModule,
+		}, nil
+	default:
+		return s.loadLibrary(module)
+	}
+}
+
+// loadLibrary executes a Starlark file and returns its globals.  Relative
+// filenames are resolved from the directory of the script, or the working
+// directory when using source.  Each file is only executed once.
+func (s *StarlarkCommon) loadLibrary(module string) (starlark.StringDict, error) {
+	if filepath.Ext(module) != ".star" {
+		return nil, errors.New("module " + module + " is not available")
+	}
+
+	filename := module
+	if !filepath.IsAbs(filename) && s.Script != "" {
+		filename = filepath.Join(filepath.Dir(s.Script), filename)
+	}
+	filename = filepath.Clean(filename)
+
+	if lib, ok := s.libraries[filename]; ok {
+		if lib == nil {
+			return nil, fmt.Errorf("cycle in load graph of %s", module)
+		}
+		return lib.globals, lib.err
+	}
+
+	// Mark the library as loading to detect cycles.
+	s.libraries[filename] = nil
+
+	thread := &starlark.Thread{
+		Name:  "load " + filename,
+		Print: s.thread.Print,
+		Load:  s.load,
+	}
+	globals, err := starlark.ExecFile(thread, filename, nil, s.builtins)
+	if err != nil {
+		err = fmt.Errorf("loading %s failed: %v", module, err)
+	} else {
+		globals.Freeze()
+	}
+
+	s.libraries[filename] = &library{globals: globals, err: err}
+	return globals, err
+}
+
+func init() {
+	// https://github.com/bazelbuild/starlark/issues/20
+	resolve.AllowNestedDef = true
+	resolve.AllowLambda = true
+	resolve.AllowFloat = true
+	resolve.AllowSet = true
+	resolve.AllowGlobalReassign = true
+	resolve.AllowRecursion = true
+}
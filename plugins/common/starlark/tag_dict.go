@@ -85,6 +85,10 @@ func (d TagDict) Get(key starlark.Value) (v starlark.Value, found bool, err erro
 // SetKey implements the starlark.HasSetKey interface to support map update
 // using x[k]=v syntax, like a dictionary.
 func (d TagDict) SetKey(k, v starlark.Value) error {
+	if d.frozen {
+		return errors.New("cannot modify frozen metric")
+	}
+
 	if d.tagIterCount > 0 {
 		return fmt.Errorf("cannot insert during iteration")
 	}
@@ -116,6 +120,10 @@ func (d TagDict) Items() []starlark.Tuple {
 }
 
 func (d TagDict) Clear() error {
+	if d.frozen {
+		return errors.New("cannot modify frozen metric")
+	}
+
 	if d.tagIterCount > 0 {
 		return fmt.Errorf("cannot delete during iteration")
 	}
@@ -132,6 +140,10 @@ func (d TagDict) Clear() error {
 }
 
 func (d TagDict) PopItem() (v starlark.Value, err error) {
+	if d.frozen {
+		return nil, errors.New("cannot modify frozen metric")
+	}
+
 	if d.tagIterCount > 0 {
 		return nil, fmt.Errorf("cannot delete during iteration")
 	}
@@ -151,6 +163,10 @@ func (d TagDict) PopItem() (v starlark.Value, err error) {
 }
 
 func (d TagDict) Delete(k starlark.Value) (v starlark.Value, found bool, err error) {
+	if d.frozen {
+		return nil, false, errors.New("cannot modify frozen metric")
+	}
+
 	if d.tagIterCount > 0 {
 		return nil, false, fmt.Errorf("cannot delete during iteration")
 	}
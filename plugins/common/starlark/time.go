@@ -0,0 +1,404 @@
+package starlark
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// TimeModule is the time module available using load("time.star", "time").
+// The functions and types follow the time library of starlark-go, which is
+// not available in the version used by Telegraf.
+var TimeModule = &starlarkstruct.Module{
+	Name: "time",
+	Members: starlark.StringDict{
+		"from_timestamp":    starlark.NewBuiltin("from_timestamp", fromTimestamp),
+		"is_valid_timezone": starlark.NewBuiltin("is_valid_timezone", isValidTimezone),
+		"now":               starlark.NewBuiltin("now", now),
+		"parse_duration":    starlark.NewBuiltin("parse_duration", parseDuration),
+		"parse_time":        starlark.NewBuiltin("parse_time", parseTime),
+		"time":              starlark.NewBuiltin("time", newTime),
+
+		"nanosecond":  Duration(time.Nanosecond),
+		"microsecond": Duration(time.Microsecond),
+		"millisecond": Duration(time.Millisecond),
+		"second":      Duration(time.Second),
+		"minute":      Duration(time.Minute),
+		"hour":        Duration(time.Hour),
+	},
+}
+
+func fromTimestamp(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var sec, nsec starlark.Int = starlark.MakeInt(0), starlark.MakeInt(0)
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "sec", &sec, "nsec?", &nsec); err != nil {
+		return nil, err
+	}
+	s, ok := sec.Int64()
+	if !ok {
+		return nil, nameErr(b, "sec out of range")
+	}
+	ns, ok := nsec.Int64()
+	if !ok {
+		return nil, nameErr(b, "nsec out of range")
+	}
+	return Time(time.Unix(s, ns)), nil
+}
+
+func isValidTimezone(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var location string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &location); err != nil {
+		return nil, err
+	}
+	_, err := time.LoadLocation(location)
+	return starlark.Bool(err == nil), nil
+}
+
+func now(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+	return Time(time.Now()), nil
+}
+
+func parseDuration(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var d Duration
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func parseTime(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var (
+		x        string
+		format   = time.RFC3339
+		location = "UTC"
+	)
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "x", &x, "format?", &format, "location?", &location); err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(location)
+	if err != nil {
+		return nil, nameErr(b, err)
+	}
+	t, err := time.ParseInLocation(format, x, loc)
+	if err != nil {
+		return nil, nameErr(b, err)
+	}
+	return Time(t), nil
+}
+
+func newTime(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var (
+		year, month, day, hour, minute, second, nanosecond int
+		location                                           = "UTC"
+	)
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs,
+		"year?", &year,
+		"month?", &month,
+		"day?", &day,
+		"hour?", &hour,
+		"minute?", &minute,
+		"second?", &second,
+		"nanosecond?", &nanosecond,
+		"location?", &location,
+	); err != nil {
+		return nil, err
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("%s: unexpected positional arguments", b.Name())
+	}
+
+	loc, err := time.LoadLocation(location)
+	if err != nil {
+		return nil, nameErr(b, err)
+	}
+	return Time(time.Date(year, time.Month(month), day, hour, minute, second, nanosecond, loc)), nil
+}
+
+// Duration is a starlark representation of a time.Duration.
+type Duration time.Duration
+
+// Unpack implements the starlark.Unpacker interface, a duration can be given
+// as Duration or as string like "1h30m".
+func (d *Duration) Unpack(v starlark.Value) error {
+	switch x := v.(type) {
+	case Duration:
+		*d = x
+		return nil
+	case starlark.String:
+		dur, err := time.ParseDuration(string(x))
+		if err != nil {
+			return err
+		}
+		*d = Duration(dur)
+		return nil
+	}
+	return fmt.Errorf("got %s, want a duration or string", v.Type())
+}
+
+func (d Duration) String() string { return time.Duration(d).String() }
+
+func (d Duration) Type() string { return "time.duration" }
+
+func (d Duration) Freeze() {}
+
+func (d Duration) Hash() (uint32, error) {
+	return uint32(d) ^ uint32(int64(d)>>32), nil
+}
+
+func (d Duration) Truth() starlark.Bool { return d != 0 }
+
+// AttrNames implements the starlark.HasAttrs interface.
+func (d Duration) AttrNames() []string {
+	return []string{"hours", "minutes", "seconds", "milliseconds", "microseconds", "nanoseconds"}
+}
+
+// Attr implements the starlark.HasAttrs interface.
+func (d Duration) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "hours":
+		return starlark.Float(time.Duration(d).Hours()), nil
+	case "minutes":
+		return starlark.Float(time.Duration(d).Minutes()), nil
+	case "seconds":
+		return starlark.Float(time.Duration(d).Seconds()), nil
+	case "milliseconds":
+		return starlark.MakeInt64(int64(d) / int64(time.Millisecond)), nil
+	case "microseconds":
+		return starlark.MakeInt64(int64(d) / int64(time.Microsecond)), nil
+	case "nanoseconds":
+		return starlark.MakeInt64(int64(d)), nil
+	}
+	return nil, nil
+}
+
+// CompareSameType implements the starlark.Comparable interface.
+func (d Duration) CompareSameType(op syntax.Token, y starlark.Value, depth int) (bool, error) {
+	return compare(op, int64(d), int64(y.(Duration)))
+}
+
+// Unary implements the starlark.HasUnary interface.
+func (d Duration) Unary(op syntax.Token) (starlark.Value, error) {
+	switch op {
+	case syntax.PLUS:
+		return d, nil
+	case syntax.MINUS:
+		return -d, nil
+	}
+	return nil, nil
+}
+
+// Binary implements the starlark.HasBinary interface:
+//
+//	duration + duration = duration
+//	duration + time = time
+//	duration - duration = duration
+//	duration / duration = float
+//	duration / int = duration
+//	duration // duration = int
+//	duration * int = duration
+func (d Duration) Binary(op syntax.Token, y starlark.Value, side starlark.Side) (starlark.Value, error) {
+	x := time.Duration(d)
+
+	switch op {
+	case syntax.PLUS:
+		switch y := y.(type) {
+		case Duration:
+			return Duration(x + time.Duration(y)), nil
+		case Time:
+			return Time(time.Time(y).Add(x)), nil
+		}
+	case syntax.MINUS:
+		if y, ok := y.(Duration); ok {
+			if side == starlark.Left {
+				return Duration(x - time.Duration(y)), nil
+			}
+			return Duration(time.Duration(y) - x), nil
+		}
+	case syntax.SLASH:
+		switch y := y.(type) {
+		case Duration:
+			if y == 0 {
+				return nil, errors.New("division by zero")
+			}
+			if side == starlark.Left {
+				return starlark.Float(float64(x) / float64(y)), nil
+			}
+			if x == 0 {
+				return nil, errors.New("division by zero")
+			}
+			return starlark.Float(float64(y) / float64(x)), nil
+		case starlark.Int:
+			if side == starlark.Left {
+				i, ok := y.Int64()
+				if !ok {
+					return nil, errors.New("int value out of range")
+				}
+				if i == 0 {
+					return nil, errors.New("division by zero")
+				}
+				return Duration(x / time.Duration(i)), nil
+			}
+		}
+	case syntax.SLASHSLASH:
+		if y, ok := y.(Duration); ok {
+			if side == starlark.Left {
+				if y == 0 {
+					return nil, errors.New("division by zero")
+				}
+				return starlark.MakeInt64(int64(x) / int64(y)), nil
+			}
+			if x == 0 {
+				return nil, errors.New("division by zero")
+			}
+			return starlark.MakeInt64(int64(y) / int64(x)), nil
+		}
+	case syntax.STAR:
+		if y, ok := y.(starlark.Int); ok {
+			i, ok := y.Int64()
+			if !ok {
+				return nil, errors.New("int value out of range")
+			}
+			return Duration(x * time.Duration(i)), nil
+		}
+	}
+	return nil, nil
+}
+
+// Time is a starlark representation of a time.Time.
+type Time time.Time
+
+func (t Time) String() string { return time.Time(t).String() }
+
+func (t Time) Type() string { return "time.time" }
+
+func (t Time) Freeze() {}
+
+func (t Time) Hash() (uint32, error) {
+	nanos := time.Time(t).UnixNano()
+	return uint32(nanos) ^ uint32(nanos>>32), nil
+}
+
+func (t Time) Truth() starlark.Bool { return starlark.Bool(!time.Time(t).IsZero()) }
+
+// AttrNames implements the starlark.HasAttrs interface.
+func (t Time) AttrNames() []string {
+	return append(builtinAttrNames(timeMethods),
+		"year", "month", "day", "hour", "minute", "second", "nanosecond", "unix", "unix_nano")
+}
+
+// Attr implements the starlark.HasAttrs interface.
+func (t Time) Attr(name string) (starlark.Value, error) {
+	tm := time.Time(t)
+	switch name {
+	case "year":
+		return starlark.MakeInt(tm.Year()), nil
+	case "month":
+		return starlark.MakeInt(int(tm.Month())), nil
+	case "day":
+		return starlark.MakeInt(tm.Day()), nil
+	case "hour":
+		return starlark.MakeInt(tm.Hour()), nil
+	case "minute":
+		return starlark.MakeInt(tm.Minute()), nil
+	case "second":
+		return starlark.MakeInt(tm.Second()), nil
+	case "nanosecond":
+		return starlark.MakeInt(tm.Nanosecond()), nil
+	case "unix":
+		return starlark.MakeInt64(tm.Unix()), nil
+	case "unix_nano":
+		return starlark.MakeInt64(tm.UnixNano()), nil
+	}
+	return builtinAttr(t, name, timeMethods)
+}
+
+// CompareSameType implements the starlark.Comparable interface.
+func (t Time) CompareSameType(op syntax.Token, y starlark.Value, depth int) (bool, error) {
+	x, yt := time.Time(t), time.Time(y.(Time))
+	cmp := 0
+	if x.Before(yt) {
+		cmp = -1
+	} else if x.After(yt) {
+		cmp = 1
+	}
+	return compare(op, int64(cmp), 0)
+}
+
+// Binary implements the starlark.HasBinary interface:
+//
+//	time + duration = time
+//	time - duration = time
+//	time - time = duration
+func (t Time) Binary(op syntax.Token, y starlark.Value, side starlark.Side) (starlark.Value, error) {
+	x := time.Time(t)
+
+	switch op {
+	case syntax.PLUS:
+		if y, ok := y.(Duration); ok {
+			return Time(x.Add(time.Duration(y))), nil
+		}
+	case syntax.MINUS:
+		switch y := y.(type) {
+		case Duration:
+			if side == starlark.Left {
+				return Time(x.Add(-time.Duration(y))), nil
+			}
+		case Time:
+			if side == starlark.Left {
+				return Duration(x.Sub(time.Time(y))), nil
+			}
+			return Duration(time.Time(y).Sub(x)), nil
+		}
+	}
+	return nil, nil
+}
+
+var timeMethods = map[string]builtinMethod{
+	"format":      timeFormat,
+	"in_location": timeInLocation,
+}
+
+func timeFormat(b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var layout string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &layout); err != nil {
+		return nil, err
+	}
+	return starlark.String(time.Time(b.Receiver().(Time)).Format(layout)), nil
+}
+
+func timeInLocation(b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var location string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &location); err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(location)
+	if err != nil {
+		return nil, nameErr(b, err)
+	}
+	return Time(time.Time(b.Receiver().(Time)).In(loc)), nil
+}
+
+func compare(op syntax.Token, x, y int64) (bool, error) {
+	switch op {
+	case syntax.EQL:
+		return x == y, nil
+	case syntax.NEQ:
+		return x != y, nil
+	case syntax.LT:
+		return x < y, nil
+	case syntax.LE:
+		return x <= y, nil
+	case syntax.GT:
+		return x > y, nil
+	case syntax.GE:
+		return x >= y, nil
+	}
+	return false, fmt.Errorf("%s is not a valid comparison operator", op)
+}
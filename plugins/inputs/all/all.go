@@ -73,6 +73,7 @@ import (
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/jenkins"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/jolokia"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/jolokia2"
+	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/journald"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/jti_openconfig_telemetry"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/kafka_consumer"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/kafka_consumer_legacy"
@@ -123,6 +124,7 @@ import (
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/openldap"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/openntpd"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/opensmtpd"
+	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/opentelemetry"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/openweathermap"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/passenger"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/pf"
@@ -155,6 +157,7 @@ import (
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/snmp_trap"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/socket_listener"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/solr"
+	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/sql"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/sqlserver"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/stackdriver"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/statsd"
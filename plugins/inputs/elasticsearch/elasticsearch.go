@@ -13,6 +13,7 @@ import (
 
 	"github.com/shanas-swi/telegraf-v1.16.3"
 	"github.com/shanas-swi/telegraf-v1.16.3/internal"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/auth"
 	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/tls"
 	"github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs"
 	jsonparser "github.com/shanas-swi/telegraf-v1.16.3/plugins/parsers/json"
@@ -129,6 +130,19 @@ const sampleConfig = `
   # username = ""
   # password = ""
 
+  ## Optional OAuth2 Client Credentials Grant
+  # client_id = "clientid"
+  # client_secret = "secret"
+  # token_url = "https://indentityprovider/oauth2/v1/token"
+  # scopes = ["urn:opc:idm:__myscopes__"]
+
+  ## Optional file with a bearer token, re-read whenever it changes
+  # bearer_token_file = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+  ## Optional headers with their value read from a file, re-read whenever it
+  ## changes
+  # header_files = {"X-API-Key" = "/etc/telegraf/api-key"}
+
   ## Optional TLS Config
   # tls_ca = "/etc/telegraf/ca.pem"
   # tls_cert = "/etc/telegraf/cert.pem"
@@ -153,6 +167,7 @@ type Elasticsearch struct {
 	Username                   string            `toml:"username"`
 	Password                   string            `toml:"password"`
 	tls.ClientConfig
+	auth.HTTPClientConfig
 
 	client          *http.Client
 	serverInfo      map[string]serverInfo
@@ -317,9 +332,12 @@ func (e *Elasticsearch) createHTTPClient() (*http.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	tr := &http.Transport{
+	tr, err := e.HTTPClientConfig.Transport(&http.Transport{
 		ResponseHeaderTimeout: e.HTTPTimeout.Duration,
 		TLSClientConfig:       tlsCfg,
+	})
+	if err != nil {
+		return nil, err
 	}
 	client := &http.Client{
 		Transport: tr,
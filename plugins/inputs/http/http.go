@@ -1,16 +1,20 @@
 package http
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/shanas-swi/telegraf-v1.16.3"
 	"github.com/shanas-swi/telegraf-v1.16.3/internal"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/auth"
 	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/tls"
 	"github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs"
 	"github.com/shanas-swi/telegraf-v1.16.3/plugins/parsers"
@@ -28,6 +32,7 @@ type HTTP struct {
 	Username string `toml:"username"`
 	Password string `toml:"password"`
 	tls.ClientConfig
+	auth.HTTPClientConfig
 
 	// Absolute path to file with Bearer token
 	BearerToken string `toml:"bearer_token"`
@@ -36,8 +41,22 @@ type HTTP struct {
 
 	Timeout internal.Duration `toml:"timeout"`
 
+	// Render the URLs and the body as templates
+	UseTemplates bool `toml:"use_templates"`
+
+	Pagination Pagination `toml:"pagination"`
+
+	Log telegraf.Logger `toml:"-"`
+
 	client *http.Client
 
+	urlTemplates []*template.Template
+	bodyTemplate *template.Template
+
+	// Time of the last successful gather of each URL
+	lastGather []time.Time
+	mu         sync.Mutex
+
 	// The parser will automatically be set by Telegraf core code because
 	// this plugin implements the ParserInput interface (i.e. the SetParser method)
 	parser parsers.Parser
@@ -63,9 +82,28 @@ var sampleConfig = `
   # username = "username"
   # password = "pa$$word"
 
+  ## Optional OAuth2 Client Credentials Grant
+  # client_id = "clientid"
+  # client_secret = "secret"
+  # token_url = "https://indentityprovider/oauth2/v1/token"
+  # scopes = ["urn:opc:idm:__myscopes__"]
+
+  ## Optional file with a bearer token, re-read whenever it changes
+  # bearer_token_file = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+  ## Optional headers with their value read from a file, re-read whenever it
+  ## changes
+  # header_files = {"X-API-Key" = "/etc/telegraf/api-key"}
+
   ## HTTP entity-body to send with POST/PUT requests.
   # body = ""
 
+  ## Render the URLs and the body as Go templates, {{.LastGather}} is the
+  ## time of the previous successful request of the URL and {{.Now}} the time
+  ## of the current one.
+  ##   ex: "http://localhost/events?since={{.LastGather.Unix}}"
+  # use_templates = false
+
   ## HTTP Content-Encoding for write request body, can be set to "gzip" to
   ## compress body or "identity" to apply no encoding.
   # content_encoding = "identity"
@@ -83,6 +121,28 @@ var sampleConfig = `
   ## List of success status codes
   # success_status_codes = [200]
 
+  ## Request the following pages of paginated responses.
+  # [inputs.http.pagination]
+  ## Pagination strategy:
+  ##   link   - follow the link with rel="next" in the Link header
+  ##   cursor - set a query parameter to the cursor found in the response
+  ##   offset - increment an offset query parameter by the limit
+  ##   page   - increment a page number query parameter
+  ## The offset and page strategies stop at the first page without metrics.
+  #   strategy = "link"
+  ## Maximum number of pages requested per URL and gather.
+  #   max_pages = 100
+  ## Cursor strategy: GJSON path of the cursor and name of the parameter.
+  #   cursor_path = "meta.next_cursor"
+  #   cursor_param = "cursor"
+  ## Offset strategy: names of the parameters and number of items per page.
+  #   offset_param = "offset"
+  #   limit_param = "limit"
+  #   limit = 100
+  ## Page strategy: name of the parameter and number of the first page.
+  #   page_param = "page"
+  #   first_page = 1
+
   ## Data format to consume.
   ## Each data format has its own unique set of configuration options, read
   ## more about them here:
@@ -106,18 +166,50 @@ func (h *HTTP) Init() error {
 		return err
 	}
 
+	transport, err := h.HTTPClientConfig.Transport(&http.Transport{
+		TLSClientConfig: tlsCfg,
+		Proxy:           http.ProxyFromEnvironment,
+	})
+	if err != nil {
+		return err
+	}
+
 	h.client = &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: tlsCfg,
-			Proxy:           http.ProxyFromEnvironment,
-		},
-		Timeout: h.Timeout.Duration,
+		Transport: transport,
+		Timeout:   h.Timeout.Duration,
 	}
 
 	// Set default as [200]
 	if len(h.SuccessStatusCodes) == 0 {
 		h.SuccessStatusCodes = []int{200}
 	}
+
+	if err := h.Pagination.init(); err != nil {
+		return err
+	}
+
+	if h.UseTemplates {
+		h.urlTemplates = make([]*template.Template, 0, len(h.URLs))
+		for _, u := range h.URLs {
+			tmpl, err := template.New("url").Parse(u)
+			if err != nil {
+				return fmt.Errorf("invalid url template %q: %v", u, err)
+			}
+			h.urlTemplates = append(h.urlTemplates, tmpl)
+		}
+		h.bodyTemplate, err = template.New("body").Parse(h.Body)
+		if err != nil {
+			return fmt.Errorf("invalid body template: %v", err)
+		}
+	}
+
+	// Until the first successful request the time the plugin was started
+	// is used as the time of the last gather.
+	now := time.Now()
+	h.lastGather = make([]time.Time, len(h.URLs))
+	for i := range h.lastGather {
+		h.lastGather[i] = now
+	}
 	return nil
 }
 
@@ -125,14 +217,15 @@ func (h *HTTP) Init() error {
 // gathers. This is called every "interval"
 func (h *HTTP) Gather(acc telegraf.Accumulator) error {
 	var wg sync.WaitGroup
-	for _, u := range h.URLs {
+	now := time.Now()
+	for i, u := range h.URLs {
 		wg.Add(1)
-		go func(url string) {
+		go func(index int, url string) {
 			defer wg.Done()
-			if err := h.gatherURL(acc, url); err != nil {
+			if err := h.gatherURL(acc, index, now); err != nil {
 				acc.AddError(fmt.Errorf("[url=%s]: %s", url, err))
 			}
-		}(u)
+		}(i, u)
 	}
 
 	wg.Wait()
@@ -145,34 +238,104 @@ func (h *HTTP) SetParser(parser parsers.Parser) {
 	h.parser = parser
 }
 
-// Gathers data from a particular URL
+// templateData is passed to the URL and body templates.
+type templateData struct {
+	LastGather time.Time
+	Now        time.Time
+}
+
+// Gathers data from a particular URL, following the pages of the response if
+// pagination is enabled
 // Parameters:
 //
 //	acc    : The telegraf Accumulator to use
-//	url    : endpoint to send request to
+//	index  : index of the URL in the configuration
+//	now    : time of the current gather
 //
 // Returns:
 //
 //	error: Any error that may have occurred
 func (h *HTTP) gatherURL(
 	acc telegraf.Accumulator,
-	url string,
+	index int,
+	now time.Time,
 ) error {
-	body, err := makeRequestBodyReader(h.ContentEncoding, h.Body)
+	address := h.URLs[index]
+	body := h.Body
+	if h.UseTemplates {
+		h.mu.Lock()
+		data := templateData{LastGather: h.lastGather[index], Now: now}
+		h.mu.Unlock()
+
+		var err error
+		address, err = execTemplate(h.urlTemplates[index], data)
+		if err != nil {
+			return err
+		}
+		body, err = execTemplate(h.bodyTemplate, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	u, err := url.Parse(address)
 	if err != nil {
 		return err
 	}
-	defer body.Close()
 
-	request, err := http.NewRequest(h.Method, url, body)
+	pages := h.Pagination.newPaginator(u)
+	for next := pages.first(); next != nil; {
+		resp, b, err := h.request(next.String(), body)
+		if err != nil {
+			return err
+		}
+
+		metrics, err := h.parser.Parse(b)
+		if err != nil {
+			return err
+		}
+
+		for _, metric := range metrics {
+			if !metric.HasTag("url") {
+				metric.AddTag("url", h.URLs[index])
+			}
+			acc.AddFields(metric.Name(), metric.Fields(), metric.Tags(), metric.Time())
+		}
+
+		next, err = pages.next(resp, b, len(metrics))
+		if err != nil {
+			return err
+		}
+	}
+	if pages.limitReached {
+		h.Log.Warnf("Stopped requesting pages of %q after reaching max_pages (%d)", h.URLs[index], h.Pagination.MaxPages)
+	}
+
+	h.mu.Lock()
+	h.lastGather[index] = now
+	h.mu.Unlock()
+
+	return nil
+}
+
+// request sends a request to the given URL and returns the response together
+// with its body.
+func (h *HTTP) request(address string, body string) (*http.Response, []byte, error) {
+	reader, err := makeRequestBodyReader(h.ContentEncoding, body)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+	defer reader.Close()
+
+	request, err := http.NewRequest(h.Method, address, reader)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	if h.BearerToken != "" {
 		token, err := ioutil.ReadFile(h.BearerToken)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 		bearer := "Bearer " + strings.Trim(string(token), "\n")
 		request.Header.Set("Authorization", bearer)
@@ -196,7 +359,7 @@ func (h *HTTP) gatherURL(
 
 	resp, err := h.client.Do(request)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
@@ -209,7 +372,7 @@ func (h *HTTP) gatherURL(
 	}
 
 	if !responseHasSuccessCode {
-		return fmt.Errorf("received status code %d (%s), expected any value out of %v",
+		return nil, nil, fmt.Errorf("received status code %d (%s), expected any value out of %v",
 			resp.StatusCode,
 			http.StatusText(resp.StatusCode),
 			h.SuccessStatusCodes)
@@ -217,22 +380,17 @@ func (h *HTTP) gatherURL(
 
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return err
-	}
-
-	metrics, err := h.parser.Parse(b)
-	if err != nil {
-		return err
+		return nil, nil, err
 	}
+	return resp, b, nil
+}
 
-	for _, metric := range metrics {
-		if !metric.HasTag("url") {
-			metric.AddTag("url", url)
-		}
-		acc.AddFields(metric.Name(), metric.Fields(), metric.Tags(), metric.Time())
+func execTemplate(tmpl *template.Template, data templateData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
 	}
-
-	return nil
+	return buf.String(), nil
 }
 
 func makeRequestBodyReader(contentEncoding, body string) (io.ReadCloser, error) {
@@ -252,6 +410,9 @@ func init() {
 		return &HTTP{
 			Timeout: internal.Duration{Duration: time.Second * 5},
 			Method:  "GET",
+			Pagination: Pagination{
+				FirstPage: 1,
+			},
 		}
 	})
 }
@@ -6,8 +6,11 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/auth"
 	plugin "github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs/http"
 	"github.com/shanas-swi/telegraf-v1.16.3/plugins/parsers"
 	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
@@ -252,3 +255,252 @@ func TestBodyAndContentEncoding(t *testing.T) {
 		})
 	}
 }
+
+func TestPagination(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	url := ts.URL + "/items"
+
+	tests := []struct {
+		name       string
+		pagination plugin.Pagination
+		handler    func(t *testing.T, w http.ResponseWriter, r *http.Request)
+	}{
+		{
+			name:       "link",
+			pagination: plugin.Pagination{Strategy: "link"},
+			handler: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Query().Get("p") {
+				case "":
+					w.Header().Add("Link", `<http://example.org/>; rel="prev", </items?p=2>; rel="next"`)
+					fmt.Fprintln(w, `{"items": [{"value": 1}, {"value": 2}]}`)
+				case "2":
+					w.Header().Add("Link", `</items?p=1>; rel="prev first"`)
+					fmt.Fprintln(w, `{"items": [{"value": 3}]}`)
+				}
+			},
+		},
+		{
+			name:       "cursor",
+			pagination: plugin.Pagination{Strategy: "cursor", CursorPath: "next"},
+			handler: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Query().Get("cursor") {
+				case "":
+					fmt.Fprintln(w, `{"next": "abc", "items": [{"value": 1}, {"value": 2}]}`)
+				case "abc":
+					fmt.Fprintln(w, `{"next": null, "items": [{"value": 3}]}`)
+				}
+			},
+		},
+		{
+			name:       "offset",
+			pagination: plugin.Pagination{Strategy: "offset", Limit: 2},
+			handler: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "2", r.URL.Query().Get("limit"))
+				switch r.URL.Query().Get("offset") {
+				case "0":
+					fmt.Fprintln(w, `{"items": [{"value": 1}, {"value": 2}]}`)
+				case "2":
+					fmt.Fprintln(w, `{"items": [{"value": 3}]}`)
+				case "4":
+					fmt.Fprintln(w, `{"items": []}`)
+				}
+			},
+		},
+		{
+			name:       "page",
+			pagination: plugin.Pagination{Strategy: "page", FirstPage: 1},
+			handler: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Query().Get("page") {
+				case "1":
+					fmt.Fprintln(w, `{"items": [{"value": 1}, {"value": 2}]}`)
+				case "2":
+					fmt.Fprintln(w, `{"items": [{"value": 3}]}`)
+				default:
+					fmt.Fprintln(w, `{"items": []}`)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/items", r.URL.Path)
+				tt.handler(t, w, r)
+			})
+
+			plugin := &plugin.HTTP{
+				URLs:       []string{url},
+				Pagination: tt.pagination,
+			}
+			parser, err := parsers.NewParser(&parsers.Config{
+				DataFormat: "json",
+				MetricName: "items",
+				JSONQuery:  "items",
+			})
+			require.NoError(t, err)
+			plugin.SetParser(parser)
+			require.NoError(t, plugin.Init())
+
+			var acc testutil.Accumulator
+			require.NoError(t, acc.GatherError(plugin.Gather))
+
+			require.Len(t, acc.Metrics, 3)
+			for i, m := range acc.Metrics {
+				require.Equal(t, float64(i+1), m.Fields["value"])
+				require.Equal(t, url, m.Tags["url"])
+			}
+		})
+	}
+}
+
+func TestPaginationMaxPages(t *testing.T) {
+	var since []string
+	fakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			since = append(since, r.URL.Query().Get("since"))
+		}
+		w.Header().Add("Link", `</endpoint?page=2>; rel="next"`)
+		_, _ = w.Write([]byte(simpleJSON))
+	}))
+	defer fakeServer.Close()
+
+	plugin := &plugin.HTTP{
+		URLs:         []string{fakeServer.URL + "/endpoint?since={{.LastGather.UnixNano}}"},
+		Pagination:   plugin.Pagination{Strategy: "link", MaxPages: 3},
+		UseTemplates: true,
+		Log:          testutil.Logger{},
+	}
+	p, _ := parsers.NewParser(&parsers.Config{
+		DataFormat: "json",
+		MetricName: "metricName",
+	})
+	plugin.SetParser(p)
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(plugin.Gather))
+	require.Len(t, acc.Metrics, 3)
+
+	// Reaching max_pages is no error, the next gather requests a new window
+	require.NoError(t, acc.GatherError(plugin.Gather))
+	require.Len(t, acc.Metrics, 6)
+	require.Len(t, since, 2)
+	require.NotEqual(t, since[0], since[1])
+}
+
+func TestPaginationInitError(t *testing.T) {
+	input := &plugin.HTTP{
+		Pagination: plugin.Pagination{Strategy: "cursor"},
+	}
+	require.Error(t, input.Init())
+
+	input.Pagination = plugin.Pagination{Strategy: "unknown"}
+	require.Error(t, input.Init())
+}
+
+func TestTemplating(t *testing.T) {
+	var since []string
+	var bodies []string
+	fakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		since = append(since, r.URL.Query().Get("since"))
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+		_, _ = w.Write([]byte(simpleJSON))
+	}))
+	defer fakeServer.Close()
+
+	url := fakeServer.URL + "/endpoint?since={{.LastGather.UnixNano}}"
+	plugin := &plugin.HTTP{
+		URLs:         []string{url},
+		Method:       "POST",
+		Body:         `{"from": {{.LastGather.UnixNano}}, "to": {{.Now.UnixNano}}}`,
+		UseTemplates: true,
+	}
+	p, _ := parsers.NewParser(&parsers.Config{
+		DataFormat: "json",
+		MetricName: "metricName",
+	})
+	plugin.SetParser(p)
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(plugin.Gather))
+	require.NoError(t, acc.GatherError(plugin.Gather))
+
+	require.Len(t, since, 2)
+	require.NotEqual(t, since[0], since[1])
+	// The window of a request starts at the end of the previous one
+	require.Equal(t, fmt.Sprintf(`{"from": %s, "to": %s}`, since[0], since[1]), bodies[0])
+	require.Equal(t, url, acc.Metrics[0].Tags["url"])
+}
+
+func TestTemplatingDisabled(t *testing.T) {
+	var paths []string
+	var bodies []string
+	fakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RawQuery)
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+		_, _ = w.Write([]byte(simpleJSON))
+	}))
+	defer fakeServer.Close()
+
+	// Bodies such as mustache templates of Elasticsearch queries are sent as
+	// they are
+	body := `{"id": "metrics", "params": {"from": "{{from}}", "size": {{#size}}10{{/size}}}}`
+	plugin := &plugin.HTTP{
+		URLs:   []string{fakeServer.URL + "/endpoint?q={{.Now}}"},
+		Method: "POST",
+		Body:   body,
+	}
+	p, _ := parsers.NewParser(&parsers.Config{
+		DataFormat: "json",
+		MetricName: "metricName",
+	})
+	plugin.SetParser(p)
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(plugin.Gather))
+	require.Equal(t, []string{"q={{.Now}}"}, paths)
+	require.Equal(t, []string{body}, bodies)
+}
+
+func TestBearerTokenFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "http")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("abc123\n"), 0600))
+
+	fakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer abc123" {
+			_, _ = w.Write([]byte(simpleJSON))
+		} else {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer fakeServer.Close()
+
+	plugin := &plugin.HTTP{
+		URLs: []string{fakeServer.URL},
+		HTTPClientConfig: auth.HTTPClientConfig{
+			BearerTokenFile: tokenFile,
+		},
+	}
+	p, _ := parsers.NewParser(&parsers.Config{
+		DataFormat: "json",
+		MetricName: "metricName",
+	})
+	plugin.SetParser(p)
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, acc.GatherError(plugin.Gather))
+	require.Len(t, acc.Metrics, 1)
+}
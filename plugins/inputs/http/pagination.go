@@ -0,0 +1,198 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Pagination configures how the following pages of a response are requested.
+type Pagination struct {
+	// One of "link", "cursor", "offset" or "page"
+	Strategy string `toml:"strategy"`
+	MaxPages int    `toml:"max_pages"`
+
+	// Cursor strategy
+	CursorPath  string `toml:"cursor_path"`
+	CursorParam string `toml:"cursor_param"`
+
+	// Offset strategy
+	OffsetParam string `toml:"offset_param"`
+	LimitParam  string `toml:"limit_param"`
+	Limit       int    `toml:"limit"`
+
+	// Page strategy
+	PageParam string `toml:"page_param"`
+	FirstPage int    `toml:"first_page"`
+}
+
+func (p *Pagination) init() error {
+	switch p.Strategy {
+	case "":
+		return nil
+	case "link":
+	case "cursor":
+		if p.CursorPath == "" {
+			return fmt.Errorf("cursor_path is required for the cursor pagination")
+		}
+		if p.CursorParam == "" {
+			p.CursorParam = "cursor"
+		}
+	case "offset":
+		if p.OffsetParam == "" {
+			p.OffsetParam = "offset"
+		}
+		if p.LimitParam == "" {
+			p.LimitParam = "limit"
+		}
+		if p.Limit <= 0 {
+			return fmt.Errorf("limit must be positive for the offset pagination")
+		}
+	case "page":
+		if p.PageParam == "" {
+			p.PageParam = "page"
+		}
+	default:
+		return fmt.Errorf("unknown pagination strategy %q", p.Strategy)
+	}
+
+	if p.MaxPages <= 0 {
+		p.MaxPages = 100
+	}
+	return nil
+}
+
+// paginator tracks the pagination state while requesting the pages of a
+// single URL.
+type paginator struct {
+	*Pagination
+
+	base   *url.URL
+	pages  int
+	offset int
+	page   int
+	cursor string
+
+	// The last page was not reached before max_pages
+	limitReached bool
+}
+
+func (p *Pagination) newPaginator(base *url.URL) *paginator {
+	return &paginator{Pagination: p, base: base, page: p.FirstPage}
+}
+
+// first returns the URL of the first page.
+func (pg *paginator) first() *url.URL {
+	switch pg.Strategy {
+	case "offset":
+		return pg.withParams(map[string]string{
+			pg.OffsetParam: "0",
+			pg.LimitParam:  strconv.Itoa(pg.Limit),
+		})
+	case "page":
+		return pg.withParams(map[string]string{
+			pg.PageParam: strconv.Itoa(pg.page),
+		})
+	}
+	return pg.base
+}
+
+// next returns the URL of the page following the given response, or nil if
+// it was the last page or max_pages is reached.  The number of metrics parsed
+// from the response is used to detect the end of offset and page based
+// pagination.
+func (pg *paginator) next(resp *http.Response, body []byte, metrics int) (*url.URL, error) {
+	u, err := pg.nextURL(resp, body, metrics)
+	if err != nil || u == nil {
+		return nil, err
+	}
+
+	pg.pages++
+	if pg.pages >= pg.MaxPages {
+		pg.limitReached = true
+		return nil, nil
+	}
+	return u, nil
+}
+
+func (pg *paginator) nextURL(resp *http.Response, body []byte, metrics int) (*url.URL, error) {
+	switch pg.Strategy {
+	case "link":
+		link := nextLink(resp.Header.Values("Link"))
+		if link == "" {
+			return nil, nil
+		}
+		u, err := resp.Request.URL.Parse(link)
+		if err != nil {
+			return nil, fmt.Errorf("invalid next link %q: %v", link, err)
+		}
+		return u, nil
+	case "cursor":
+		cursor := gjson.GetBytes(body, pg.CursorPath).String()
+		if cursor == "" || cursor == pg.cursor {
+			return nil, nil
+		}
+		pg.cursor = cursor
+		return pg.withParams(map[string]string{pg.CursorParam: cursor}), nil
+	case "offset":
+		if metrics == 0 {
+			return nil, nil
+		}
+		pg.offset += pg.Limit
+		return pg.withParams(map[string]string{
+			pg.OffsetParam: strconv.Itoa(pg.offset),
+			pg.LimitParam:  strconv.Itoa(pg.Limit),
+		}), nil
+	case "page":
+		if metrics == 0 {
+			return nil, nil
+		}
+		pg.page++
+		return pg.withParams(map[string]string{
+			pg.PageParam: strconv.Itoa(pg.page),
+		}), nil
+	}
+	return nil, nil
+}
+
+// withParams returns the base URL with the given query parameters set.
+func (pg *paginator) withParams(params map[string]string) *url.URL {
+	u := *pg.base
+	query := u.Query()
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	u.RawQuery = query.Encode()
+	return &u
+}
+
+// nextLink returns the target of the link with the "next" relation in the
+// given Link header values as defined in RFC 8288.
+func nextLink(headers []string) string {
+	for _, header := range headers {
+		for _, link := range strings.Split(header, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range parts[1:] {
+				kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+				if len(kv) != 2 || !strings.EqualFold(kv[0], "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(kv[1], `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
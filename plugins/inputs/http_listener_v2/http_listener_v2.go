@@ -2,8 +2,10 @@ package http_listener_v2
 
 import (
 	"compress/gzip"
+	"context"
 	"crypto/subtle"
 	"crypto/tls"
+	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -24,11 +26,20 @@ import (
 // 500 MB
 const defaultMaxBodySize = 500 * 1024 * 1024
 
+// defaultMaxUndeliveredRequests is the default maximum number of remote
+// write requests whose metrics have not been delivered yet.
+const defaultMaxUndeliveredRequests = 1000
+
 const (
 	body  = "body"
 	query = "query"
 )
 
+const (
+	protocolDataFormat  = "data_format"
+	protocolRemoteWrite = "prometheus_remote_write"
+)
+
 // TimeFunc provides a timestamp for the metrics
 type TimeFunc func() time.Time
 
@@ -45,6 +56,10 @@ type HTTPListenerV2 struct {
 	BasicUsername  string            `toml:"basic_username"`
 	BasicPassword  string            `toml:"basic_password"`
 	HTTPHeaderTags map[string]string `toml:"http_header_tags"`
+	Protocol       string            `toml:"protocol"`
+
+	MaxUndeliveredRequests int `toml:"max_undelivered_requests"`
+
 	tlsint.ServerConfig
 
 	TimeFunc
@@ -56,6 +71,14 @@ type HTTPListenerV2 struct {
 
 	parsers.Parser
 	acc telegraf.Accumulator
+
+	// Delivery tracking of the remote write protocol
+	trackingAcc telegraf.TrackingAccumulator
+	ctx         context.Context
+	cancel      context.CancelFunc
+	mu          sync.Mutex
+	undelivered map[telegraf.TrackingID]chan bool
+	sem         chan struct{}
 }
 
 const sampleConfig = `
@@ -99,6 +122,18 @@ const sampleConfig = `
   ## If multiple instances of the http header are present, only the first value will be used
   # http_header_tags = {"HTTP_HEADER" = "TAG_NAME"}
 
+  ## Protocol of the requests, available options are:
+  ##   data_format             - parse the data_source using the data_format
+  ##   prometheus_remote_write - receive snappy compressed protobuf requests of
+  ##                             the Prometheus remote write protocol.  The
+  ##                             response is sent once the metrics are written
+  ##                             by the outputs, or failed to be written.
+  # protocol = "data_format"
+
+  ## Maximum number of remote write requests waiting for their metrics to be
+  ## written.  Further requests wait for a free slot until the write_timeout.
+  # max_undelivered_requests = 1000
+
   ## Data format to consume.
   ## Each data format has its own unique set of configuration options, read
   ## more about them here:
@@ -122,6 +157,17 @@ func (h *HTTPListenerV2) SetParser(parser parsers.Parser) {
 	h.Parser = parser
 }
 
+func (h *HTTPListenerV2) Init() error {
+	switch h.Protocol {
+	case "":
+		h.Protocol = protocolDataFormat
+	case protocolDataFormat, protocolRemoteWrite:
+	default:
+		return fmt.Errorf("unknown protocol %q", h.Protocol)
+	}
+	return nil
+}
+
 // Start starts the http listener service.
 func (h *HTTPListenerV2) Start(acc telegraf.Accumulator) error {
 	if h.MaxBodySize.Size == 0 {
@@ -137,16 +183,39 @@ func (h *HTTPListenerV2) Start(acc telegraf.Accumulator) error {
 
 	h.acc = acc
 
+	h.ctx, h.cancel = context.WithCancel(context.Background())
+	if h.Protocol == protocolRemoteWrite {
+		if h.MaxUndeliveredRequests <= 0 {
+			h.MaxUndeliveredRequests = defaultMaxUndeliveredRequests
+		}
+		h.trackingAcc = acc.WithTracking(h.MaxUndeliveredRequests)
+		h.sem = make(chan struct{}, h.MaxUndeliveredRequests)
+		h.undelivered = make(map[telegraf.TrackingID]chan bool)
+
+		h.wg.Add(1)
+		go func() {
+			defer h.wg.Done()
+			h.receiveDelivered()
+		}()
+	}
+
 	tlsConf, err := h.ServerConfig.TLSConfig()
 	if err != nil {
 		return err
 	}
 
+	// Remote write requests wait up to the write_timeout for the delivery of
+	// their metrics, leave some time to send the response afterwards.
+	writeTimeout := h.WriteTimeout.Duration
+	if h.Protocol == protocolRemoteWrite {
+		writeTimeout += time.Second
+	}
+
 	server := &http.Server{
 		Addr:         h.ServiceAddress,
 		Handler:      h,
 		ReadTimeout:  h.ReadTimeout.Duration,
-		WriteTimeout: h.WriteTimeout.Duration,
+		WriteTimeout: writeTimeout,
 		TLSConfig:    tlsConf,
 	}
 
@@ -157,6 +226,8 @@ func (h *HTTPListenerV2) Start(acc telegraf.Accumulator) error {
 		listener, err = net.Listen("tcp", h.ServiceAddress)
 	}
 	if err != nil {
+		h.cancel()
+		h.wg.Wait()
 		return err
 	}
 	h.listener = listener
@@ -175,12 +246,16 @@ func (h *HTTPListenerV2) Start(acc telegraf.Accumulator) error {
 
 // Stop cleans up all resources
 func (h *HTTPListenerV2) Stop() {
+	h.cancel()
 	h.listener.Close()
 	h.wg.Wait()
 }
 
 func (h *HTTPListenerV2) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	handler := h.serveWrite
+	if h.Protocol == protocolRemoteWrite {
+		handler = h.serveRemoteWrite
+	}
 
 	if req.URL.Path != h.Path {
 		handler = http.NotFound
@@ -299,6 +374,18 @@ func internalServerError(res http.ResponseWriter) {
 	res.WriteHeader(http.StatusInternalServerError)
 }
 
+func unsupportedMediaType(res http.ResponseWriter) {
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(http.StatusUnsupportedMediaType)
+	res.Write([]byte(`{"error":"http: unsupported media type"}`))
+}
+
+func serviceUnavailable(res http.ResponseWriter) {
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(http.StatusServiceUnavailable)
+	res.Write([]byte(`{"error":"http: metrics not written"}`))
+}
+
 func badRequest(res http.ResponseWriter) {
 	res.Header().Set("Content-Type", "application/json")
 	res.WriteHeader(http.StatusBadRequest)
@@ -329,6 +416,9 @@ func init() {
 			Path:           "/telegraf",
 			Methods:        []string{"POST", "PUT"},
 			DataSource:     body,
+			Protocol:       protocolDataFormat,
+
+			MaxUndeliveredRequests: defaultMaxUndeliveredRequests,
 		}
 	})
 }
@@ -0,0 +1,224 @@
+package http_listener_v2
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/metric"
+)
+
+// The messages of the Prometheus remote write protocol, see
+// https://github.com/prometheus/prometheus/blob/main/prompb/remote.proto
+// Only the fields used by the listener are declared, others are skipped.
+
+type WriteRequest struct {
+	Timeseries []*TimeSeries `protobuf:"bytes,1,rep,name=timeseries,proto3" json:"timeseries,omitempty"`
+}
+
+func (m *WriteRequest) Reset()         { *m = WriteRequest{} }
+func (m *WriteRequest) String() string { return proto.CompactTextString(m) }
+func (*WriteRequest) ProtoMessage()    {}
+
+type TimeSeries struct {
+	Labels  []*Label  `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty"`
+	Samples []*Sample `protobuf:"bytes,2,rep,name=samples,proto3" json:"samples,omitempty"`
+}
+
+func (m *TimeSeries) Reset()         { *m = TimeSeries{} }
+func (m *TimeSeries) String() string { return proto.CompactTextString(m) }
+func (*TimeSeries) ProtoMessage()    {}
+
+type Label struct {
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *Label) Reset()         { *m = Label{} }
+func (m *Label) String() string { return proto.CompactTextString(m) }
+func (*Label) ProtoMessage()    {}
+
+type Sample struct {
+	Value     float64 `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	Timestamp int64   `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (m *Sample) Reset()         { *m = Sample{} }
+func (m *Sample) String() string { return proto.CompactTextString(m) }
+func (*Sample) ProtoMessage()    {}
+
+const remoteWriteMeasurement = "prometheus_remote_write"
+
+// serveRemoteWrite handles a request of the Prometheus remote write protocol.
+// The request is only answered once its metrics are delivered, so that the
+// sender retries requests which could not be written.  Prometheus retries
+// requests answered with a 5xx status and drops those answered with a 4xx
+// status.
+func (h *HTTPListenerV2) serveRemoteWrite(res http.ResponseWriter, req *http.Request) {
+	if req.ContentLength > h.MaxBodySize.Size {
+		tooLarge(res)
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		methodNotAllowed(res)
+		return
+	}
+
+	if req.Header.Get("Content-Encoding") != "snappy" {
+		unsupportedMediaType(res)
+		return
+	}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || mediaType != "application/x-protobuf" {
+			unsupportedMediaType(res)
+			return
+		}
+	}
+
+	body := http.MaxBytesReader(res, req.Body, h.MaxBodySize.Size)
+	compressed, err := ioutil.ReadAll(body)
+	if err != nil {
+		tooLarge(res)
+		return
+	}
+
+	metrics, err := h.parseRemoteWrite(compressed)
+	if err != nil {
+		h.Log.Debugf("Parse error: %s", err.Error())
+		badRequest(res)
+		return
+	}
+
+	if len(metrics) == 0 {
+		res.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	for _, m := range metrics {
+		for headerName, measurementName := range h.HTTPHeaderTags {
+			headerValues := req.Header.Get(headerName)
+			if len(headerValues) > 0 {
+				m.AddTag(measurementName, headerValues)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), h.WriteTimeout.Duration)
+	defer cancel()
+
+	// Wait for a free slot, the slot is released once the metrics are
+	// delivered
+	select {
+	case <-ctx.Done():
+		serviceUnavailable(res)
+		return
+	case <-h.ctx.Done():
+		serviceUnavailable(res)
+		return
+	case h.sem <- struct{}{}:
+	}
+
+	// The lock is held while adding the metrics, so the request is known
+	// before its delivery is reported.  Metrics dropped by the filters of the
+	// input are reported as delivered while they are added.
+	ch := make(chan bool, 1)
+	h.mu.Lock()
+	id := h.trackingAcc.AddTrackingMetricGroup(metrics)
+	h.undelivered[id] = ch
+	h.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		h.Log.Debugf("Timeout waiting for the delivery of %d metrics", len(metrics))
+		serviceUnavailable(res)
+	case <-h.ctx.Done():
+		serviceUnavailable(res)
+	case delivered := <-ch:
+		if delivered {
+			res.WriteHeader(http.StatusNoContent)
+		} else {
+			serviceUnavailable(res)
+		}
+	}
+}
+
+// receiveDelivered answers the requests waiting for the delivery of their
+// metrics.
+func (h *HTTPListenerV2) receiveDelivered() {
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case info := <-h.trackingAcc.Delivered():
+			<-h.sem
+
+			h.mu.Lock()
+			ch, ok := h.undelivered[info.ID()]
+			delete(h.undelivered, info.ID())
+			h.mu.Unlock()
+			if !ok {
+				continue
+			}
+
+			if !info.Delivered() {
+				h.Log.Debug("Metric group failed to process")
+			}
+			ch <- info.Delivered()
+		}
+	}
+}
+
+// parseRemoteWrite decodes a snappy compressed WriteRequest.  Each sample is
+// converted to a metric with a field named after the metric name and the
+// labels as tags.  NaN values, such as Prometheus staleness markers, are
+// skipped.
+func (h *HTTPListenerV2) parseRemoteWrite(compressed []byte) ([]telegraf.Metric, error) {
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("snappy decoding failed: %v", err)
+	}
+
+	var request WriteRequest
+	if err := proto.Unmarshal(data, &request); err != nil {
+		return nil, fmt.Errorf("unmarshaling WriteRequest failed: %v", err)
+	}
+
+	var metrics []telegraf.Metric
+	for _, ts := range request.Timeseries {
+		var name string
+		tags := make(map[string]string, len(ts.Labels))
+		for _, label := range ts.Labels {
+			if label.Name == "__name__" {
+				name = label.Value
+				continue
+			}
+			tags[label.Name] = label.Value
+		}
+		if name == "" {
+			return nil, fmt.Errorf("time series without metric name")
+		}
+
+		for _, sample := range ts.Samples {
+			if math.IsNaN(sample.Value) {
+				continue
+			}
+
+			t := time.Unix(0, sample.Timestamp*int64(time.Millisecond))
+			m, err := metric.New(remoteWriteMeasurement, tags, map[string]interface{}{name: sample.Value}, t)
+			if err != nil {
+				return nil, err
+			}
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics, nil
+}
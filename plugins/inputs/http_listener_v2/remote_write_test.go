@@ -0,0 +1,195 @@
+package http_listener_v2
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/agent"
+	"github.com/shanas-swi/telegraf-v1.16.3/internal"
+	"github.com/shanas-swi/telegraf-v1.16.3/models"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type testMetricMaker struct{}
+
+func (tm *testMetricMaker) LogName() string {
+	return "test"
+}
+
+func (tm *testMetricMaker) MakeMetric(metric telegraf.Metric) telegraf.Metric {
+	return metric
+}
+
+func (tm *testMetricMaker) Log() telegraf.Logger {
+	return models.NewLogger("test", "test", "")
+}
+
+func newTestRemoteWriteListener() *HTTPListenerV2 {
+	return &HTTPListenerV2{
+		Log:                    testutil.Logger{},
+		ServiceAddress:         "localhost:0",
+		Path:                   "/receive",
+		Methods:                []string{"POST"},
+		TimeFunc:               time.Now,
+		MaxBodySize:            internal.Size{Size: 70000},
+		WriteTimeout:           internal.Duration{Duration: time.Second},
+		Protocol:               protocolRemoteWrite,
+		MaxUndeliveredRequests: 1,
+		HTTPHeaderTags:         map[string]string{"X-Prometheus-Remote-Write-Version": "version"},
+	}
+}
+
+func newWriteRequest(t *testing.T) []byte {
+	request := &WriteRequest{
+		Timeseries: []*TimeSeries{
+			{
+				Labels: []*Label{
+					{Name: "__name__", Value: "go_goroutines"},
+					{Name: "job", Value: "prometheus"},
+				},
+				Samples: []*Sample{
+					{Value: 42, Timestamp: 1614889298859},
+					{Value: math.NaN(), Timestamp: 1614889313859},
+				},
+			},
+			{
+				Labels: []*Label{
+					{Name: "__name__", Value: "up"},
+					{Name: "job", Value: "node"},
+				},
+				Samples: []*Sample{
+					{Value: 1, Timestamp: 1614889298859},
+				},
+			},
+		},
+	}
+	data, err := proto.Marshal(request)
+	require.NoError(t, err)
+	return snappy.Encode(nil, data)
+}
+
+func postRemoteWrite(t *testing.T, listener *HTTPListenerV2, body []byte, encoding string) int {
+	req, err := http.NewRequest("POST", createURL(listener, "http", "/receive", ""), bytes.NewBuffer(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", encoding)
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestParseRemoteWrite(t *testing.T) {
+	listener := newTestRemoteWriteListener()
+
+	metrics, err := listener.parseRemoteWrite(newWriteRequest(t))
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"prometheus_remote_write",
+			map[string]string{"job": "prometheus"},
+			map[string]interface{}{"go_goroutines": 42.0},
+			time.Unix(0, 1614889298859*int64(time.Millisecond)),
+		),
+		testutil.MustMetric(
+			"prometheus_remote_write",
+			map[string]string{"job": "node"},
+			map[string]interface{}{"up": 1.0},
+			time.Unix(0, 1614889298859*int64(time.Millisecond)),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics)
+
+	_, err = listener.parseRemoteWrite([]byte("not snappy"))
+	require.Error(t, err)
+}
+
+func TestRemoteWriteDelivery(t *testing.T) {
+	listener := newTestRemoteWriteListener()
+	require.NoError(t, listener.Init())
+
+	dst := make(chan telegraf.Metric, 10)
+	require.NoError(t, listener.Start(agent.NewAccumulator(&testMetricMaker{}, dst)))
+	defer listener.Stop()
+
+	// Written metrics are acknowledged
+	done := make(chan int)
+	go func() { done <- postRemoteWrite(t, listener, newWriteRequest(t), "snappy") }()
+	var received []telegraf.Metric
+	for i := 0; i < 2; i++ {
+		m := <-dst
+		require.Equal(t, "0.1.0", m.Tags()["version"])
+		received = append(received, m)
+	}
+	for _, m := range received {
+		m.Accept()
+	}
+	require.Equal(t, http.StatusNoContent, <-done)
+
+	// Metrics which could not be written are retried by the sender
+	go func() { done <- postRemoteWrite(t, listener, newWriteRequest(t), "snappy") }()
+	(<-dst).Reject()
+	(<-dst).Accept()
+	require.Equal(t, http.StatusServiceUnavailable, <-done)
+
+	// Requests time out if the metrics are not written in time
+	require.Equal(t, http.StatusServiceUnavailable, postRemoteWrite(t, listener, newWriteRequest(t), "snappy"))
+	(<-dst).Accept()
+	(<-dst).Accept()
+}
+
+func TestRemoteWriteFiltered(t *testing.T) {
+	listener := newTestRemoteWriteListener()
+	require.NoError(t, listener.Init())
+
+	// All metrics of the requests are dropped by the filter of the input
+	input := models.NewRunningInput(listener, &models.InputConfig{
+		Name:   "http_listener_v2",
+		Filter: models.Filter{NameDrop: []string{"*"}},
+	})
+	require.NoError(t, input.Config.Filter.Compile())
+
+	dst := make(chan telegraf.Metric, 10)
+	require.NoError(t, listener.Start(agent.NewAccumulator(input, dst)))
+	defer listener.Stop()
+
+	for i := 0; i < 3; i++ {
+		require.Equal(t, http.StatusNoContent, postRemoteWrite(t, listener, newWriteRequest(t), "snappy"))
+	}
+	require.Empty(t, dst)
+}
+
+func TestRemoteWriteInvalidRequests(t *testing.T) {
+	listener := newTestRemoteWriteListener()
+	require.NoError(t, listener.Init())
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, listener.Start(acc))
+	defer listener.Stop()
+
+	require.Equal(t, http.StatusUnsupportedMediaType, postRemoteWrite(t, listener, newWriteRequest(t), "gzip"))
+	require.Equal(t, http.StatusBadRequest, postRemoteWrite(t, listener, []byte("not snappy"), "snappy"))
+
+	resp, err := http.Get(createURL(listener, "http", "/receive", ""))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	require.Equal(t, 0, len(acc.Metrics))
+}
+
+func TestInitUnknownProtocol(t *testing.T) {
+	listener := newTestRemoteWriteListener()
+	listener.Protocol = "unknown"
+	require.Error(t, listener.Init())
+}
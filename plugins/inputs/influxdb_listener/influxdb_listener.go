@@ -37,8 +37,11 @@ type InfluxDBListener struct {
 	BasicPassword      string            `toml:"basic_password"`
 	DatabaseTag        string            `toml:"database_tag"`
 	RetentionPolicyTag string            `toml:"retention_policy_tag"`
+	SchemaFile         string            `toml:"schema_file"`
+	SchemaAction       string            `toml:"schema_action"`
 
 	timeFunc influx.TimeFunc
+	schema   *influx.Schema
 
 	listener net.Listener
 	server   http.Server
@@ -83,6 +86,14 @@ const sampleConfig = `
   ## the value of this tag name.
   # retention_policy_tag = ""
 
+  ## Schema file declaring the field types and required tags of measurements,
+  ## see the influx data format for the file layout.
+  # schema_file = "/etc/telegraf/schema.toml"
+
+  ## Action for metrics not matching the schema, one of "coerce", "drop" or
+  ## "reject".  Rejected metrics are reported to the client like parse errors.
+  # schema_action = "coerce"
+
   ## Set one or more allowed client CA certificate file names to
   ## enable mutually authenticated TLS connections
   tls_allowed_cacerts = ["/etc/telegraf/clientca.pem"]
@@ -152,6 +163,14 @@ func (h *InfluxDBListener) Init() error {
 		h.WriteTimeout.Duration = time.Second * 10
 	}
 
+	if h.SchemaFile != "" {
+		schema, err := influx.LoadSchema(h.SchemaFile, h.SchemaAction)
+		if err != nil {
+			return err
+		}
+		h.schema = schema
+	}
+
 	return nil
 }
 
@@ -278,6 +297,9 @@ func (h *InfluxDBListener) handleWrite() http.HandlerFunc {
 
 		parser := influx.NewStreamParser(body)
 		parser.SetTimeFunc(h.timeFunc)
+		if h.schema != nil {
+			parser.SetSchema(h.schema)
+		}
 
 		precisionStr := req.URL.Query().Get("precision")
 		if precisionStr != "" {
@@ -304,10 +326,11 @@ func (h *InfluxDBListener) handleWrite() http.HandlerFunc {
 			h.bytesRecv.Incr(int64(pos - lastPos))
 			lastPos = pos
 
-			// Continue parsing metrics even if some are malformed
-			if parseErr, ok := err.(*influx.ParseError); ok {
+			// Continue parsing metrics even if some are malformed or
+			// rejected by the schema
+			if isPartialError(err) {
 				parseErrorCount += 1
-				errStr := parseErr.Error()
+				errStr := err.Error()
 				if firstParseErrorStr == "" {
 					firstParseErrorStr = errStr
 				}
@@ -353,6 +376,15 @@ func (h *InfluxDBListener) handleWrite() http.HandlerFunc {
 	}
 }
 
+func isPartialError(err error) bool {
+	switch err.(type) {
+	case *influx.ParseError, *influx.SchemaError:
+		return true
+	default:
+		return false
+	}
+}
+
 func tooLarge(res http.ResponseWriter) {
 	res.Header().Set("Content-Type", "application/json")
 	res.Header().Set("X-Influxdb-Version", "1.0")
@@ -0,0 +1,148 @@
+package journald
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cursor is the position of an entry in the journal.  Its string form is
+// the cursor format of sd-journal, so cursors can be used with journalctl
+// --after-cursor.
+type cursor struct {
+	seqnumID  [16]byte
+	seqnum    uint64
+	bootID    [16]byte
+	monotonic uint64
+	realtime  uint64
+	xorHash   uint64
+}
+
+func (c cursor) String() string {
+	return fmt.Sprintf("s=%x;i=%x;b=%x;m=%x;t=%x;x=%x",
+		c.seqnumID, c.seqnum, c.bootID, c.monotonic, c.realtime, c.xorHash)
+}
+
+func parseCursor(s string) (cursor, error) {
+	var c cursor
+	seen := map[string]bool{}
+	for _, item := range strings.Split(strings.TrimSpace(s), ";") {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			return c, fmt.Errorf("invalid cursor %q", s)
+		}
+
+		var err error
+		switch kv[0] {
+		case "s":
+			err = parseID(kv[1], &c.seqnumID)
+		case "i":
+			c.seqnum, err = strconv.ParseUint(kv[1], 16, 64)
+		case "b":
+			err = parseID(kv[1], &c.bootID)
+		case "m":
+			c.monotonic, err = strconv.ParseUint(kv[1], 16, 64)
+		case "t":
+			c.realtime, err = strconv.ParseUint(kv[1], 16, 64)
+		case "x":
+			c.xorHash, err = strconv.ParseUint(kv[1], 16, 64)
+		default:
+			// Ignore unknown items for compatibility
+			continue
+		}
+		if err != nil {
+			return c, fmt.Errorf("invalid cursor %q: %v", s, err)
+		}
+		seen[kv[0]] = true
+	}
+
+	for _, k := range []string{"s", "i", "b", "m", "t", "x"} {
+		if !seen[k] {
+			return c, fmt.Errorf("invalid cursor %q: missing %q", s, k)
+		}
+	}
+	return c, nil
+}
+
+func parseID(s string, id *[16]byte) error {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	if len(b) != len(id) {
+		return fmt.Errorf("invalid id %q", s)
+	}
+	copy(id[:], b)
+	return nil
+}
+
+// compare orders two entries in the same way as sd-journal: by sequence
+// number if both were written with the same sequence number source, by
+// monotonic time if both were written during the same boot and by wall clock
+// time otherwise.
+func (c cursor) compare(other cursor) int {
+	if c.seqnumID == other.seqnumID {
+		return compareUint(c.seqnum, other.seqnum)
+	}
+	if c.bootID == other.bootID {
+		if r := compareUint(c.monotonic, other.monotonic); r != 0 {
+			return r
+		}
+	}
+	if r := compareUint(c.realtime, other.realtime); r != 0 {
+		return r
+	}
+	return compareUint(c.xorHash, other.xorHash)
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func loadCursor(filename string) (*cursor, error) {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return nil, nil
+	}
+
+	c, err := parseCursor(string(data))
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// saveCursor writes the cursor to a temporary file which is renamed, so the
+// cursor file is never left partially written.
+func saveCursor(filename string, c cursor) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), filepath.Base(filename)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(c.String() + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
@@ -0,0 +1,342 @@
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4"
+)
+
+// The journal file format is documented at
+// https://systemd.io/JOURNAL_FILE_FORMAT/
+
+var journalSignature = []byte("LPKSHHRH")
+
+// Incompatible header flags
+const (
+	incompatibleCompressedXZ   = 1 << 0
+	incompatibleCompressedLZ4  = 1 << 1
+	incompatibleKeyedHash      = 1 << 2
+	incompatibleCompressedZSTD = 1 << 3
+	incompatibleCompact        = 1 << 4
+
+	incompatibleSupported = incompatibleCompressedXZ | incompatibleCompressedLZ4 |
+		incompatibleKeyedHash | incompatibleCompressedZSTD | incompatibleCompact
+)
+
+// Object types
+const (
+	objectData       = 1
+	objectEntry      = 3
+	objectEntryArray = 6
+)
+
+// Object flags
+const (
+	objectCompressedXZ   = 1 << 0
+	objectCompressedLZ4  = 1 << 1
+	objectCompressedZSTD = 1 << 2
+)
+
+const (
+	objectHeaderSize = 16
+	minHeaderSize    = 208
+
+	// Upper limit of the size of a single object, larger objects are
+	// considered corrupt.
+	maxObjectSize = 64 * 1024 * 1024
+)
+
+var errXZUnsupported = errors.New("xz compressed journal data is not supported")
+
+// journalHeader contains the header fields used to read the entries.
+type journalHeader struct {
+	incompatibleFlags uint32
+	fileID            [16]byte
+	seqnumID          [16]byte
+	headerSize        uint64
+	nEntries          uint64
+	tailEntrySeqnum   uint64
+	entryArrayOffset  uint64
+	tailEntryRealtime uint64
+}
+
+// journalFile reads the entries of a journal file.  The file may be written
+// concurrently by journald; only the entries counted in the header are read.
+type journalFile struct {
+	path   string
+	file   *os.File
+	size   int64
+	header journalHeader
+}
+
+func openJournal(path string) (*journalFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	j := &journalFile{path: path, file: file}
+	if err := j.readHeader(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("reading %q failed: %v", path, err)
+	}
+	return j, nil
+}
+
+func (j *journalFile) Close() error {
+	return j.file.Close()
+}
+
+func (j *journalFile) compact() bool {
+	return j.header.incompatibleFlags&incompatibleCompact != 0
+}
+
+func (j *journalFile) readHeader() error {
+	info, err := j.file.Stat()
+	if err != nil {
+		return err
+	}
+	j.size = info.Size()
+
+	buf := make([]byte, minHeaderSize)
+	if _, err := j.file.ReadAt(buf, 0); err != nil {
+		return fmt.Errorf("invalid header: %v", err)
+	}
+	if !bytes.Equal(buf[0:8], journalSignature) {
+		return errors.New("not a journal file")
+	}
+
+	h := &j.header
+	h.incompatibleFlags = binary.LittleEndian.Uint32(buf[12:16])
+	if unknown := h.incompatibleFlags &^ incompatibleSupported; unknown != 0 {
+		return fmt.Errorf("unsupported incompatible flags %#x", unknown)
+	}
+	copy(h.fileID[:], buf[24:40])
+	copy(h.seqnumID[:], buf[72:88])
+	h.headerSize = binary.LittleEndian.Uint64(buf[88:96])
+	h.nEntries = binary.LittleEndian.Uint64(buf[152:160])
+	h.tailEntrySeqnum = binary.LittleEndian.Uint64(buf[160:168])
+	h.entryArrayOffset = binary.LittleEndian.Uint64(buf[176:184])
+	h.tailEntryRealtime = binary.LittleEndian.Uint64(buf[192:200])
+
+	if h.headerSize < minHeaderSize {
+		return fmt.Errorf("invalid header size %d", h.headerSize)
+	}
+	return nil
+}
+
+// readObject returns the object at the given offset, after verifying its
+// type.  If limit is positive at most limit bytes of the object are read.
+func (j *journalFile) readObject(offset uint64, typ uint8, limit uint64) ([]byte, error) {
+	if offset < j.header.headerSize || offset%8 != 0 || offset+objectHeaderSize > uint64(j.size) {
+		return nil, fmt.Errorf("invalid object offset %d", offset)
+	}
+
+	head := make([]byte, objectHeaderSize)
+	if _, err := j.file.ReadAt(head, int64(offset)); err != nil {
+		return nil, err
+	}
+	if head[0] != typ {
+		return nil, fmt.Errorf("object at offset %d has type %d, expected %d", offset, head[0], typ)
+	}
+	size := binary.LittleEndian.Uint64(head[8:16])
+	if size < objectHeaderSize || size > maxObjectSize || offset+size > uint64(j.size) {
+		return nil, fmt.Errorf("invalid size %d of object at offset %d", size, offset)
+	}
+	if limit > 0 && size > limit {
+		size = limit
+	}
+
+	obj := make([]byte, size)
+	if _, err := j.file.ReadAt(obj, int64(offset)); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// entryOffsets returns the offsets of the entry objects in the order they
+// were written, skipping the first skip entries.
+func (j *journalFile) entryOffsets(skip uint64) ([]uint64, error) {
+	itemSize := uint64(8)
+	if j.compact() {
+		itemSize = 4
+	}
+
+	var offsets []uint64
+	var n uint64
+	offset := j.header.entryArrayOffset
+	for offset != 0 && n < j.header.nEntries {
+		obj, err := j.readObject(offset, objectEntryArray, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(obj) < 24 {
+			return nil, fmt.Errorf("invalid entry array at offset %d", offset)
+		}
+
+		items := obj[24:]
+		for i := uint64(0); (i+1)*itemSize <= uint64(len(items)) && n < j.header.nEntries; i++ {
+			var item uint64
+			if itemSize == 4 {
+				item = uint64(binary.LittleEndian.Uint32(items[i*4:]))
+			} else {
+				item = binary.LittleEndian.Uint64(items[i*8:])
+			}
+			if item == 0 {
+				// Unused items at the end of the last array
+				return offsets, nil
+			}
+			if n >= skip {
+				offsets = append(offsets, item)
+			}
+			n++
+		}
+		offset = binary.LittleEndian.Uint64(obj[16:24])
+	}
+	return offsets, nil
+}
+
+// entry is a journal entry with its fields.
+type entry struct {
+	cursor
+	fields map[string]string
+}
+
+// readCursor reads the position of the entry at the given offset without
+// reading its fields.
+func (j *journalFile) readCursor(offset uint64) (cursor, error) {
+	obj, err := j.readObject(offset, objectEntry, 64)
+	if err != nil {
+		return cursor{}, err
+	}
+	if len(obj) < 64 {
+		return cursor{}, fmt.Errorf("invalid entry at offset %d", offset)
+	}
+	return j.parseCursor(obj), nil
+}
+
+func (j *journalFile) parseCursor(obj []byte) cursor {
+	c := cursor{
+		seqnumID:  j.header.seqnumID,
+		seqnum:    binary.LittleEndian.Uint64(obj[16:24]),
+		realtime:  binary.LittleEndian.Uint64(obj[24:32]),
+		monotonic: binary.LittleEndian.Uint64(obj[32:40]),
+		xorHash:   binary.LittleEndian.Uint64(obj[56:64]),
+	}
+	copy(c.bootID[:], obj[40:56])
+	return c
+}
+
+// readEntry reads the entry at the given offset including its fields.
+func (j *journalFile) readEntry(offset uint64) (*entry, error) {
+	obj, err := j.readObject(offset, objectEntry, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(obj) < 64 {
+		return nil, fmt.Errorf("invalid entry at offset %d", offset)
+	}
+
+	e := &entry{
+		cursor: j.parseCursor(obj),
+		fields: map[string]string{},
+	}
+
+	itemSize := 16
+	if j.compact() {
+		itemSize = 4
+	}
+	items := obj[64:]
+	for i := 0; (i+1)*itemSize <= len(items); i++ {
+		var dataOffset uint64
+		if itemSize == 4 {
+			dataOffset = uint64(binary.LittleEndian.Uint32(items[i*4:]))
+		} else {
+			dataOffset = binary.LittleEndian.Uint64(items[i*16:])
+		}
+
+		payload, err := j.readData(dataOffset)
+		if err != nil {
+			return nil, err
+		}
+		eq := bytes.IndexByte(payload, '=')
+		if eq <= 0 {
+			continue
+		}
+		e.fields[string(payload[:eq])] = string(payload[eq+1:])
+	}
+	return e, nil
+}
+
+// readData returns the decompressed payload of a data object.
+func (j *journalFile) readData(offset uint64) ([]byte, error) {
+	obj, err := j.readObject(offset, objectData, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	start := 64
+	if j.compact() {
+		start = 72
+	}
+	if len(obj) < start {
+		return nil, fmt.Errorf("invalid data object at offset %d", offset)
+	}
+	payload := obj[start:]
+
+	switch flags := obj[1]; {
+	case flags&objectCompressedXZ != 0:
+		return nil, errXZUnsupported
+	case flags&objectCompressedLZ4 != 0:
+		return decompressLZ4(payload)
+	case flags&objectCompressedZSTD != 0:
+		return decompressZSTD(payload)
+	}
+	return payload, nil
+}
+
+// decompressLZ4 decompresses an LZ4 block prefixed with its decompressed
+// size as written by journald.
+func decompressLZ4(data []byte) ([]byte, error) {
+	if len(data) < 8 {
+		return nil, errors.New("invalid lz4 compressed data")
+	}
+	size := binary.LittleEndian.Uint64(data[:8])
+	if size > maxObjectSize {
+		return nil, fmt.Errorf("invalid lz4 decompressed size %d", size)
+	}
+
+	buf := make([]byte, size)
+	n, err := lz4.UncompressBlock(data[8:], buf)
+	if err != nil {
+		return nil, fmt.Errorf("lz4 decompression failed: %v", err)
+	}
+	return buf[:n], nil
+}
+
+var (
+	zstdOnce    sync.Once
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+func decompressZSTD(data []byte) ([]byte, error) {
+	zstdOnce.Do(func() {
+		zstdDecoder, zstdErr = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	})
+	if zstdErr != nil {
+		return nil, zstdErr
+	}
+
+	buf, err := zstdDecoder.DecodeAll(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("zstd decompression failed: %v", err)
+	}
+	return buf, nil
+}
@@ -0,0 +1,368 @@
+package journald
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/filter"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs"
+)
+
+const sampleConfig = `
+  ## Directories containing journal files, searched recursively for files
+  ## with the ".journal" extension.
+  # paths = ["/var/log/journal", "/run/log/journal"]
+
+  ## File storing the position in the journal, used to continue reading
+  ## after a restart.  If not set, or the file does not exist yet, reading
+  ## starts at the end of the journal.
+  # cursor_file = ""
+
+  ## Read the entries already in the journal when no cursor is available.
+  # from_beginning = false
+
+  ## Only read the entries of units matching one of these glob patterns.
+  # units = ["sshd.service", "nginx*"]
+
+  ## Only read entries with this or a higher priority, one of "emerg",
+  ## "alert", "crit", "err", "warning", "notice", "info" or "debug".
+  # priority = "debug"
+
+  ## Journal fields added as tags, mapped to the tag name.  The PRIORITY and
+  ## SYSLOG_FACILITY fields are converted to their names.
+  # [inputs.journald.tag_mapping]
+  #   _SYSTEMD_UNIT = "unit"
+  #   SYSLOG_IDENTIFIER = "identifier"
+  #   PRIORITY = "severity"
+
+  ## Journal fields added as fields, mapped to the field name.
+  # [inputs.journald.field_mapping]
+  #   MESSAGE = "message"
+`
+
+const measurement = "journald"
+
+// Names of the syslog priorities used by the PRIORITY field, the priority is
+// the index.
+var priorityNames = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// Names of the syslog facilities used by the SYSLOG_FACILITY field, the
+// facility is the index.
+var facilityNames = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp", "ntp", "security", "console", "solaris-cron",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+var (
+	defaultTagMapping = map[string]string{
+		"_SYSTEMD_UNIT":     "unit",
+		"SYSLOG_IDENTIFIER": "identifier",
+		"PRIORITY":          "severity",
+	}
+	defaultFieldMapping = map[string]string{
+		"MESSAGE": "message",
+	}
+)
+
+type Journald struct {
+	Paths         []string          `toml:"paths"`
+	CursorFile    string            `toml:"cursor_file"`
+	FromBeginning bool              `toml:"from_beginning"`
+	Units         []string          `toml:"units"`
+	Priority      string            `toml:"priority"`
+	TagMapping    map[string]string `toml:"tag_mapping"`
+	FieldMapping  map[string]string `toml:"field_mapping"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	unitFilter  filter.Filter
+	maxPriority int
+
+	initialized bool
+	cursor      *cursor
+	// Number of entries read from each journal file.  Files are identified
+	// by their ID, which does not change when journald archives a file.
+	read map[[16]byte]uint64
+}
+
+func (*Journald) SampleConfig() string {
+	return sampleConfig
+}
+
+func (*Journald) Description() string {
+	return "Read entries from the systemd journal files"
+}
+
+func (j *Journald) Init() error {
+	if len(j.Paths) == 0 {
+		return fmt.Errorf("no paths configured")
+	}
+
+	var err error
+	if j.unitFilter, err = filter.Compile(j.Units); err != nil {
+		return fmt.Errorf("invalid units: %v", err)
+	}
+
+	j.maxPriority = len(priorityNames) - 1
+	if j.Priority != "" {
+		j.maxPriority = indexOf(priorityNames, j.Priority)
+		if j.maxPriority < 0 {
+			return fmt.Errorf("unknown priority %q", j.Priority)
+		}
+	}
+
+	if j.TagMapping == nil {
+		j.TagMapping = defaultTagMapping
+	}
+	if j.FieldMapping == nil {
+		j.FieldMapping = defaultFieldMapping
+	}
+
+	j.read = map[[16]byte]uint64{}
+	return nil
+}
+
+func (j *Journald) Gather(acc telegraf.Accumulator) error {
+	files, err := j.journalFiles()
+	if err != nil {
+		return err
+	}
+
+	if !j.initialized {
+		if err := j.seek(files); err != nil {
+			return err
+		}
+		j.initialized = true
+	}
+
+	var entries []*entry
+	read := make(map[[16]byte]uint64, len(j.read))
+	for _, path := range files {
+		fileEntries, err := j.readFile(path, read)
+		if err != nil {
+			acc.AddError(err)
+		}
+		entries = append(entries, fileEntries...)
+	}
+	// Forget the progress of removed files
+	j.read = read
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool {
+		return entries[a].compare(entries[b].cursor) < 0
+	})
+	for _, e := range entries {
+		j.addEntry(acc, e)
+	}
+
+	last := entries[len(entries)-1].cursor
+	j.cursor = &last
+	if j.CursorFile != "" {
+		if err := saveCursor(j.CursorFile, last); err != nil {
+			return fmt.Errorf("saving cursor failed: %v", err)
+		}
+	}
+	return nil
+}
+
+// seek sets the initial position, which is the stored cursor if available.
+// Otherwise reading starts at the beginning or at the end of the journal.
+func (j *Journald) seek(files []string) error {
+	if j.CursorFile != "" {
+		c, err := loadCursor(j.CursorFile)
+		if err != nil {
+			return fmt.Errorf("loading cursor failed: %v", err)
+		}
+		if c != nil {
+			j.cursor = c
+			return nil
+		}
+	}
+	if j.FromBeginning {
+		return nil
+	}
+
+	for _, path := range files {
+		journal, err := openJournal(path)
+		if err != nil {
+			j.Log.Errorf("Skipping journal file: %s", err.Error())
+			continue
+		}
+
+		offsets, err := journal.entryOffsets(0)
+		if err == nil && len(offsets) > 0 {
+			var c cursor
+			c, err = journal.readCursor(offsets[len(offsets)-1])
+			if err == nil && (j.cursor == nil || c.compare(*j.cursor) > 0) {
+				j.cursor = &c
+			}
+		}
+		if err != nil {
+			j.Log.Errorf("Seeking the end of %q failed: %s", path, err.Error())
+		}
+		j.read[journal.header.fileID] = uint64(len(offsets))
+		journal.Close()
+	}
+	return nil
+}
+
+// journalFiles returns the journal files in the configured directories.
+// Files ending with "~" are not included; journald renames files to this
+// name if they were not closed properly.
+func (j *Journald) journalFiles() ([]string, error) {
+	var files []string
+	for _, dir := range j.Paths {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if info.Mode().IsRegular() && strings.HasSuffix(path, ".journal") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readFile returns the entries of a journal file which were not read yet and
+// records the number of entries read in read.
+func (j *Journald) readFile(path string, read map[[16]byte]uint64) ([]*entry, error) {
+	journal, err := openJournal(path)
+	if err != nil {
+		return nil, err
+	}
+	defer journal.Close()
+
+	fileID := journal.header.fileID
+	if _, ok := read[fileID]; ok {
+		// Same file found at another path
+		return nil, nil
+	}
+
+	// The entries of files seen before are skipped using the number of
+	// entries already read.  For other files the entries are compared to the
+	// cursor.
+	skip, known := j.read[fileID]
+	if !known && j.cursor != nil && j.cursor.seqnumID == journal.header.seqnumID &&
+		journal.header.tailEntrySeqnum <= j.cursor.seqnum {
+		skip = journal.header.nEntries
+		known = true
+	}
+	read[fileID] = skip
+
+	offsets, err := journal.entryOffsets(skip)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q failed: %v", path, err)
+	}
+
+	var entries []*entry
+	for _, offset := range offsets {
+		read[fileID]++
+
+		if !known && j.cursor != nil {
+			c, err := journal.readCursor(offset)
+			if err != nil {
+				j.Log.Errorf("Skipping entry in %q: %s", path, err.Error())
+				continue
+			}
+			if c.compare(*j.cursor) <= 0 {
+				continue
+			}
+		}
+
+		e, err := journal.readEntry(offset)
+		if err != nil {
+			j.Log.Errorf("Skipping entry in %q: %s", path, err.Error())
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func (j *Journald) addEntry(acc telegraf.Accumulator, e *entry) {
+	if j.unitFilter != nil && !j.unitFilter.Match(e.fields["_SYSTEMD_UNIT"]) {
+		return
+	}
+	if j.maxPriority < len(priorityNames)-1 {
+		priority, err := strconv.Atoi(e.fields["PRIORITY"])
+		if err != nil || priority > j.maxPriority {
+			return
+		}
+	}
+
+	tags := map[string]string{}
+	for name, tag := range j.TagMapping {
+		if value, ok := e.fields[name]; ok {
+			tags[tag] = fieldValue(name, value)
+		}
+	}
+
+	fields := map[string]interface{}{}
+	for name, field := range j.FieldMapping {
+		if value, ok := e.fields[name]; ok {
+			fields[field] = fieldValue(name, value)
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	t := time.Unix(0, int64(e.realtime)*int64(time.Microsecond))
+	acc.AddFields(measurement, fields, tags, t)
+}
+
+// fieldValue converts the numeric PRIORITY and SYSLOG_FACILITY fields to
+// their names.
+func fieldValue(name, value string) string {
+	var names []string
+	switch name {
+	case "PRIORITY":
+		names = priorityNames
+	case "SYSLOG_FACILITY":
+		names = facilityNames
+	default:
+		return value
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil || i < 0 || i >= len(names) {
+		return value
+	}
+	return names[i]
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func init() {
+	inputs.Add("journald", func() telegraf.Input {
+		return &Journald{
+			Paths: []string{"/var/log/journal", "/run/log/journal"},
+		}
+	})
+}
@@ -0,0 +1,306 @@
+package journald
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pierrec/lz4"
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// The journal files in testdata were written by systemd-journald 252, one in
+// the compact and one in the regular format.  Both contain the same entries
+// and were written in this order during the same boot.
+
+var (
+	compactCursor = "s=7f57e35a72fd4445b046fac5516efb39;i=9;b=18699873b6d74596aa69ac28868070a8;m=13912937f;t=65e1d073486ac;x=642e38948806e9b1"
+	regularCursor = "s=811de116397a481f9f0cb20f30fde044;i=9;b=18699873b6d74596aa69ac28868070a8;m=13938667f;t=65e1d075a59ac;x=d6b87dafb805d91c"
+)
+
+func journalMetric(tags map[string]string, message string) telegraf.Metric {
+	return testutil.MustMetric(measurement, tags, map[string]interface{}{"message": message}, time.Unix(0, 0))
+}
+
+func expectedMetrics() []telegraf.Metric {
+	journald := map[string]string{"identifier": "systemd-journald", "severity": "info"}
+	sshd := map[string]string{"unit": "sshd.service", "identifier": "sshd", "severity": "info"}
+	return []telegraf.Metric{
+		journalMetric(journald, "Journal started"),
+		journalMetric(journald, "Runtime Journal (/run/log/journal/fed6b2924c424cf1b9a322f606b4de6d.telegraf) is 512.0K, max 4.0G, 3.9G free."),
+		journalMetric(sshd, "Server listening on 0.0.0.0 port 22."),
+		journalMetric(sshd, "Accepted publickey for admin from 192.0.2.10 port 51234 ssh2"),
+		journalMetric(map[string]string{"unit": "cron.service", "identifier": "cron", "severity": "debug"},
+			"(root) CMD (run-parts /etc/cron.hourly)"),
+		journalMetric(map[string]string{"unit": "myapp.service", "identifier": "myapp", "severity": "warning"},
+			"queue is filling up"),
+		// Long enough to be stored compressed
+		journalMetric(map[string]string{"unit": "myapp.service", "identifier": "myapp", "severity": "err"},
+			"request failed: "+strings.Repeat("timeout waiting for backend; ", 40)),
+		journalMetric(map[string]string{"unit": "sshd.service", "identifier": "sshd", "severity": "notice"},
+			"Received signal 15; terminating."),
+		journalMetric(journald, "Journal stopped"),
+	}
+}
+
+// journalDir returns a temporary directory containing the given test files.
+func journalDir(t *testing.T, files ...string) string {
+	dir, err := ioutil.TempDir("", "journald")
+	require.NoError(t, err)
+	for _, file := range files {
+		copyJournal(t, dir, file)
+	}
+	return dir
+}
+
+func copyJournal(t *testing.T, dir, file string) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", file))
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "machine"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "machine", file), data, 0644))
+}
+
+func TestGather(t *testing.T) {
+	tests := []struct {
+		file  string
+		first int64
+	}{
+		{file: "compact.journal", first: 1792328625746690},
+		{file: "regular.journal", first: 1792328628222048},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			dir := journalDir(t, tt.file)
+			defer os.RemoveAll(dir)
+
+			plugin := &Journald{
+				Paths:         []string{dir},
+				FromBeginning: true,
+				Log:           testutil.Logger{},
+			}
+			require.NoError(t, plugin.Init())
+
+			var acc testutil.Accumulator
+			require.NoError(t, plugin.Gather(&acc))
+			require.Empty(t, acc.Errors)
+			testutil.RequireMetricsEqual(t, expectedMetrics(), acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+			require.Equal(t, time.Unix(0, tt.first*int64(time.Microsecond)), acc.GetTelegrafMetrics()[0].Time())
+
+			// No new entries
+			acc.ClearMetrics()
+			require.NoError(t, plugin.Gather(&acc))
+			require.Empty(t, acc.GetTelegrafMetrics())
+		})
+	}
+}
+
+func TestGatherMultipleFiles(t *testing.T) {
+	dir := journalDir(t, "regular.journal", "compact.journal")
+	defer os.RemoveAll(dir)
+
+	plugin := &Journald{
+		Paths:         []string{dir},
+		FromBeginning: true,
+		Units:         []string{"sshd.service"},
+		Log:           testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+
+	// The entries of both files are merged in the order they were written
+	metrics := acc.GetTelegrafMetrics()
+	require.Len(t, metrics, 6)
+	for i := 1; i < len(metrics); i++ {
+		require.True(t, metrics[i-1].Time().Before(metrics[i].Time()))
+	}
+	require.Equal(t, "Received signal 15; terminating.", metrics[2].Fields()["message"])
+	require.Equal(t, "Server listening on 0.0.0.0 port 22.", metrics[3].Fields()["message"])
+}
+
+func TestGatherFilters(t *testing.T) {
+	dir := journalDir(t, "compact.journal")
+	defer os.RemoveAll(dir)
+
+	newPlugin := func(priority string) *Journald {
+		plugin := &Journald{
+			Paths:         []string{dir},
+			FromBeginning: true,
+			Units:         []string{"sshd*", "myapp.service"},
+			Priority:      priority,
+			TagMapping: map[string]string{
+				"_SYSTEMD_UNIT":   "unit",
+				"SYSLOG_FACILITY": "facility",
+			},
+			FieldMapping: map[string]string{
+				"PRIORITY":   "severity",
+				"REQUEST_ID": "request_id",
+				"CODE_LINE":  "line",
+			},
+			Log: testutil.Logger{},
+		}
+		require.NoError(t, plugin.Init())
+		return plugin
+	}
+
+	plugin := newPlugin("warning")
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(measurement,
+			map[string]string{"unit": "myapp.service"},
+			map[string]interface{}{"severity": "warning", "request_id": "abc123", "line": "42"},
+			time.Unix(0, 1792328627247511*int64(time.Microsecond)),
+		),
+		testutil.MustMetric(measurement,
+			map[string]string{"unit": "myapp.service"},
+			map[string]interface{}{"severity": "err", "request_id": "def456"},
+			time.Unix(0, 1792328627399506*int64(time.Microsecond)),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+
+	plugin = newPlugin("notice")
+
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	expected = append(expected, testutil.MustMetric(measurement,
+		map[string]string{"unit": "sshd.service", "facility": "auth"},
+		map[string]interface{}{"severity": "notice"},
+		time.Unix(0, 1792328627551586*int64(time.Microsecond)),
+	))
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestCursorPersistence(t *testing.T) {
+	dir := journalDir(t, "compact.journal")
+	defer os.RemoveAll(dir)
+	cursorFile := filepath.Join(dir, "cursor")
+
+	plugin := &Journald{
+		Paths:         []string{filepath.Join(dir, "machine")},
+		CursorFile:    cursorFile,
+		FromBeginning: true,
+		Log:           testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Len(t, acc.GetTelegrafMetrics(), 9)
+
+	content, err := ioutil.ReadFile(cursorFile)
+	require.NoError(t, err)
+	require.Equal(t, compactCursor+"\n", string(content))
+
+	// Entries written while Telegraf was stopped are read after a restart
+	copyJournal(t, dir, "regular.journal")
+	plugin = &Journald{
+		Paths:         []string{filepath.Join(dir, "machine")},
+		CursorFile:    cursorFile,
+		FromBeginning: true,
+		Log:           testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	metrics := acc.GetTelegrafMetrics()
+	testutil.RequireMetricsEqual(t, expectedMetrics(), metrics, testutil.IgnoreTime())
+	require.Equal(t, time.Unix(0, 1792328628222048*int64(time.Microsecond)), metrics[0].Time())
+
+	content, err = ioutil.ReadFile(cursorFile)
+	require.NoError(t, err)
+	require.Equal(t, regularCursor+"\n", string(content))
+
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.GetTelegrafMetrics())
+}
+
+func TestStartAtEnd(t *testing.T) {
+	dir := journalDir(t, "compact.journal")
+	defer os.RemoveAll(dir)
+
+	plugin := &Journald{
+		Paths: []string{dir},
+		Log:   testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.GetTelegrafMetrics())
+
+	copyJournal(t, dir, "regular.journal")
+	require.NoError(t, plugin.Gather(&acc))
+	testutil.RequireMetricsEqual(t, expectedMetrics(), acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestInvalidJournalFile(t *testing.T) {
+	dir := journalDir(t, "compact.journal")
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "machine", "broken.journal"), []byte("garbage"), 0644))
+
+	plugin := &Journald{
+		Paths:         []string{dir},
+		FromBeginning: true,
+		Log:           testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Len(t, acc.Errors, 1)
+	require.Len(t, acc.GetTelegrafMetrics(), 9)
+}
+
+func TestInitError(t *testing.T) {
+	plugin := &Journald{Paths: []string{"/var/log/journal"}, Priority: "verbose"}
+	require.Error(t, plugin.Init())
+
+	plugin = &Journald{}
+	require.Error(t, plugin.Init())
+}
+
+func TestParseCursor(t *testing.T) {
+	c, err := parseCursor(compactCursor)
+	require.NoError(t, err)
+	require.Equal(t, compactCursor, c.String())
+	require.Equal(t, uint64(9), c.seqnum)
+
+	other, err := parseCursor(regularCursor)
+	require.NoError(t, err)
+	require.Equal(t, -1, c.compare(other))
+	require.Equal(t, 1, other.compare(c))
+	require.Equal(t, 0, c.compare(c))
+
+	_, err = parseCursor("s=7f57e35a72fd4445b046fac5516efb39;i=9")
+	require.Error(t, err)
+	_, err = parseCursor("s=xyz;i=9;b=18699873b6d74596aa69ac28868070a8;m=1;t=1;x=1")
+	require.Error(t, err)
+}
+
+func TestDecompressLZ4(t *testing.T) {
+	data := []byte(strings.Repeat("MESSAGE=compressed by journald ", 20))
+	compressed := make([]byte, lz4.CompressBlockBound(len(data)))
+	n, err := lz4.CompressBlock(data, compressed, nil)
+	require.NoError(t, err)
+
+	payload := make([]byte, 8, 8+n)
+	payload[0] = byte(len(data))
+	payload[1] = byte(len(data) >> 8)
+	payload = append(payload, compressed[:n]...)
+
+	actual, err := decompressLZ4(payload)
+	require.NoError(t, err)
+	require.Equal(t, data, actual)
+}
@@ -0,0 +1,28 @@
+package opentelemetry
+
+import (
+	"context"
+
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/otlp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type metricsService struct {
+	receiver *OpenTelemetry
+}
+
+func registerMetricsService(server *grpc.Server, o *OpenTelemetry) {
+	otlp.RegisterMetricsServiceServer(server, &metricsService{receiver: o})
+}
+
+// Export implements the OTLP metrics service; the response is only sent once
+// the metrics have been delivered so that clients can retry on failure.
+func (s *metricsService) Export(ctx context.Context, req *otlp.ExportMetricsServiceRequest) (*otlp.ExportMetricsServiceResponse, error) {
+	metrics := convertResourceMetrics(req.GetResourceMetrics(), s.receiver.Log)
+	if err := s.receiver.deliver(ctx, metrics); err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return &otlp.ExportMetricsServiceResponse{}, nil
+}
@@ -0,0 +1,93 @@
+package opentelemetry
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/otlp"
+)
+
+const (
+	metricsPath = "/v1/metrics"
+
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeJSON     = "application/json"
+)
+
+// serveMetrics handles OTLP/HTTP export requests.
+func (o *OpenTelemetry) serveMetrics(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		res.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if req.ContentLength > o.MaxBodySize.Size {
+		res.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	contentType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || (contentType != contentTypeProtobuf && contentType != contentTypeJSON) {
+		res.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var body io.Reader = http.MaxBytesReader(res, req.Body, o.MaxBodySize.Size)
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		r, err := gzip.NewReader(body)
+		if err != nil {
+			o.Log.Debugf("Error decompressing request: %v", err)
+			res.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer r.Close()
+		body = io.LimitReader(r, o.MaxBodySize.Size)
+	}
+
+	buf, err := ioutil.ReadAll(body)
+	if err != nil {
+		res.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	exportRequest := &otlp.ExportMetricsServiceRequest{}
+	if contentType == contentTypeJSON {
+		err = jsonpb.Unmarshal(bytes.NewReader(buf), exportRequest)
+	} else {
+		err = proto.Unmarshal(buf, exportRequest)
+	}
+	if err != nil {
+		o.Log.Debugf("Error decoding request: %v", err)
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	metrics := convertResourceMetrics(exportRequest.GetResourceMetrics(), o.Log)
+	if err := o.deliver(req.Context(), metrics); err != nil {
+		res.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var response []byte
+	if contentType == contentTypeJSON {
+		var buf bytes.Buffer
+		err = (&jsonpb.Marshaler{}).Marshal(&buf, &otlp.ExportMetricsServiceResponse{})
+		response = buf.Bytes()
+	} else {
+		response, err = proto.Marshal(&otlp.ExportMetricsServiceResponse{})
+	}
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	res.Header().Set("Content-Type", contentType)
+	res.WriteHeader(http.StatusOK)
+	res.Write(response)
+}
@@ -0,0 +1,306 @@
+package opentelemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/metric"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/otlp"
+)
+
+const (
+	scopeNameTag    = "otel.scope.name"
+	scopeVersionTag = "otel.scope.version"
+	temporalityTag  = "temporality"
+)
+
+// convertResourceMetrics maps OTLP metrics onto telegraf metrics.  The field
+// layout follows the prometheus input so that the data can be handled the
+// same way by aggregators and outputs:
+//   - gauges and non-monotonic sums have a "gauge" field
+//   - monotonic sums have a "counter" field
+//   - histograms and summaries have "count" and "sum" fields, with one field
+//     per bucket upper bound or quantile.
+//
+// Resource, scope and data point attributes are added as tags.
+func convertResourceMetrics(rms []*otlp.ResourceMetrics, log telegraf.Logger) []telegraf.Metric {
+	now := time.Now()
+
+	var metrics []telegraf.Metric
+	for _, rm := range rms {
+		resourceTags := make(map[string]string)
+		addAttributes(resourceTags, rm.GetResource().GetAttributes())
+
+		for _, sm := range rm.GetScopeMetrics() {
+			scopeTags := make(map[string]string, len(resourceTags)+2)
+			for k, v := range resourceTags {
+				scopeTags[k] = v
+			}
+			if name := sm.GetScope().GetName(); name != "" {
+				scopeTags[scopeNameTag] = name
+			}
+			if version := sm.GetScope().GetVersion(); version != "" {
+				scopeTags[scopeVersionTag] = version
+			}
+			addAttributes(scopeTags, sm.GetScope().GetAttributes())
+
+			for _, m := range sm.GetMetrics() {
+				c := converter{name: m.GetName(), tags: scopeTags, now: now}
+				switch data := m.GetData().(type) {
+				case *otlp.Metric_Gauge:
+					c.gauge(data.Gauge)
+				case *otlp.Metric_Sum:
+					c.sum(data.Sum)
+				case *otlp.Metric_Histogram:
+					c.histogram(data.Histogram)
+				case *otlp.Metric_ExponentialHistogram:
+					c.exponentialHistogram(data.ExponentialHistogram)
+				case *otlp.Metric_Summary:
+					c.summary(data.Summary)
+				default:
+					log.Debugf("Ignoring metric %q with unsupported data type %T", m.GetName(), data)
+				}
+
+				for _, err := range c.errs {
+					log.Errorf("Converting metric %q: %v", m.GetName(), err)
+				}
+				metrics = append(metrics, c.metrics...)
+			}
+		}
+	}
+	return metrics
+}
+
+type converter struct {
+	name    string
+	tags    map[string]string
+	now     time.Time
+	metrics []telegraf.Metric
+	errs    []error
+}
+
+func (c *converter) add(
+	attributes []*otlp.KeyValue,
+	extraTags map[string]string,
+	timeUnixNano uint64,
+	fields map[string]interface{},
+	tp telegraf.ValueType,
+) {
+	tags := make(map[string]string, len(c.tags)+len(attributes)+len(extraTags))
+	for k, v := range c.tags {
+		tags[k] = v
+	}
+	for k, v := range extraTags {
+		tags[k] = v
+	}
+	addAttributes(tags, attributes)
+
+	t := c.now
+	if timeUnixNano != 0 {
+		t = time.Unix(0, int64(timeUnixNano))
+	}
+
+	m, err := metric.New(c.name, tags, fields, t, tp)
+	if err != nil {
+		c.errs = append(c.errs, err)
+		return
+	}
+	c.metrics = append(c.metrics, m)
+}
+
+func (c *converter) gauge(g *otlp.Gauge) {
+	for _, dp := range g.GetDataPoints() {
+		c.add(dp.GetAttributes(), nil, dp.GetTimeUnixNano(),
+			map[string]interface{}{"gauge": numberValue(dp)}, telegraf.Gauge)
+	}
+}
+
+func (c *converter) sum(s *otlp.Sum) {
+	extraTags := temporalityTags(s.GetAggregationTemporality())
+	for _, dp := range s.GetDataPoints() {
+		if s.GetIsMonotonic() {
+			c.add(dp.GetAttributes(), extraTags, dp.GetTimeUnixNano(),
+				map[string]interface{}{"counter": numberValue(dp)}, telegraf.Counter)
+		} else {
+			c.add(dp.GetAttributes(), extraTags, dp.GetTimeUnixNano(),
+				map[string]interface{}{"gauge": numberValue(dp)}, telegraf.Gauge)
+		}
+	}
+}
+
+func (c *converter) histogram(h *otlp.Histogram) {
+	extraTags := temporalityTags(h.GetAggregationTemporality())
+	for _, dp := range h.GetDataPoints() {
+		fields := map[string]interface{}{
+			"count": float64(dp.GetCount()),
+		}
+		if dp.Sum != nil {
+			fields["sum"] = dp.GetSum()
+		}
+		if dp.Min != nil {
+			fields["min"] = dp.GetMin()
+		}
+		if dp.Max != nil {
+			fields["max"] = dp.GetMax()
+		}
+
+		var cumulative uint64
+		bounds := dp.GetExplicitBounds()
+		for i, count := range dp.GetBucketCounts() {
+			cumulative += count
+			bound := math.Inf(1)
+			if i < len(bounds) {
+				bound = bounds[i]
+			}
+			fields[fmt.Sprint(bound)] = float64(cumulative)
+		}
+
+		c.add(dp.GetAttributes(), extraTags, dp.GetTimeUnixNano(), fields, telegraf.Histogram)
+	}
+}
+
+// exponentialHistogram converts the exponential buckets into cumulative
+// buckets with explicit upper bounds, as used for regular histograms.
+func (c *converter) exponentialHistogram(h *otlp.ExponentialHistogram) {
+	extraTags := temporalityTags(h.GetAggregationTemporality())
+	for _, dp := range h.GetDataPoints() {
+		fields := map[string]interface{}{
+			"count": float64(dp.GetCount()),
+		}
+		if dp.Sum != nil {
+			fields["sum"] = dp.GetSum()
+		}
+		if dp.Min != nil {
+			fields["min"] = dp.GetMin()
+		}
+		if dp.Max != nil {
+			fields["max"] = dp.GetMax()
+		}
+
+		base := math.Pow(2, math.Pow(2, -float64(dp.GetScale())))
+		var cumulative uint64
+
+		// Negative buckets cover (-base^(index+1), -base^index], so walk them
+		// from the highest index down to get ascending upper bounds.
+		negative := dp.GetNegative()
+		counts := negative.GetBucketCounts()
+		for i := len(counts) - 1; i >= 0; i-- {
+			cumulative += counts[i]
+			index := int(negative.GetOffset()) + i
+			fields[fmt.Sprint(-math.Pow(base, float64(index)))] = float64(cumulative)
+		}
+
+		cumulative += dp.GetZeroCount()
+		fields[fmt.Sprint(0.0)] = float64(cumulative)
+
+		// Positive buckets cover (base^index, base^(index+1)].
+		positive := dp.GetPositive()
+		for i, count := range positive.GetBucketCounts() {
+			cumulative += count
+			index := int(positive.GetOffset()) + i
+			fields[fmt.Sprint(math.Pow(base, float64(index+1)))] = float64(cumulative)
+		}
+		fields[fmt.Sprint(math.Inf(1))] = float64(dp.GetCount())
+
+		c.add(dp.GetAttributes(), extraTags, dp.GetTimeUnixNano(), fields, telegraf.Histogram)
+	}
+}
+
+func (c *converter) summary(s *otlp.Summary) {
+	for _, dp := range s.GetDataPoints() {
+		fields := map[string]interface{}{
+			"count": float64(dp.GetCount()),
+			"sum":   dp.GetSum(),
+		}
+		for _, q := range dp.GetQuantileValues() {
+			if !math.IsNaN(q.GetValue()) {
+				fields[fmt.Sprint(q.GetQuantile())] = q.GetValue()
+			}
+		}
+		c.add(dp.GetAttributes(), nil, dp.GetTimeUnixNano(), fields, telegraf.Summary)
+	}
+}
+
+func numberValue(dp *otlp.NumberDataPoint) interface{} {
+	switch v := dp.GetValue().(type) {
+	case *otlp.NumberDataPoint_AsInt:
+		return v.AsInt
+	case *otlp.NumberDataPoint_AsDouble:
+		return v.AsDouble
+	default:
+		return 0.0
+	}
+}
+
+func temporalityTags(temporality otlp.AggregationTemporality) map[string]string {
+	switch temporality {
+	case otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE:
+		return map[string]string{temporalityTag: "cumulative"}
+	case otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA:
+		return map[string]string{temporalityTag: "delta"}
+	default:
+		return nil
+	}
+}
+
+func addAttributes(tags map[string]string, attributes []*otlp.KeyValue) {
+	for _, kv := range attributes {
+		tags[kv.GetKey()] = attributeValue(kv.GetValue())
+	}
+}
+
+func attributeValue(v *otlp.AnyValue) string {
+	switch value := v.GetValue().(type) {
+	case *otlp.AnyValue_StringValue:
+		return value.StringValue
+	case *otlp.AnyValue_BoolValue:
+		return strconv.FormatBool(value.BoolValue)
+	case *otlp.AnyValue_IntValue:
+		return strconv.FormatInt(value.IntValue, 10)
+	case *otlp.AnyValue_DoubleValue:
+		return strconv.FormatFloat(value.DoubleValue, 'g', -1, 64)
+	case *otlp.AnyValue_BytesValue:
+		return fmt.Sprintf("%x", value.BytesValue)
+	default:
+		buf, err := json.Marshal(nativeValue(v))
+		if err != nil {
+			return ""
+		}
+		return string(buf)
+	}
+}
+
+// nativeValue converts composite attribute values so they can be rendered
+// as JSON.
+func nativeValue(v *otlp.AnyValue) interface{} {
+	switch value := v.GetValue().(type) {
+	case *otlp.AnyValue_StringValue:
+		return value.StringValue
+	case *otlp.AnyValue_BoolValue:
+		return value.BoolValue
+	case *otlp.AnyValue_IntValue:
+		return value.IntValue
+	case *otlp.AnyValue_DoubleValue:
+		return value.DoubleValue
+	case *otlp.AnyValue_BytesValue:
+		return value.BytesValue
+	case *otlp.AnyValue_ArrayValue:
+		values := make([]interface{}, 0, len(value.ArrayValue.GetValues()))
+		for _, item := range value.ArrayValue.GetValues() {
+			values = append(values, nativeValue(item))
+		}
+		return values
+	case *otlp.AnyValue_KvlistValue:
+		values := make(map[string]interface{}, len(value.KvlistValue.GetValues()))
+		for _, kv := range value.KvlistValue.GetValues() {
+			values[kv.GetKey()] = nativeValue(kv.GetValue())
+		}
+		return values
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,280 @@
+package opentelemetry
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/internal"
+	tlsint "github.com/shanas-swi/telegraf-v1.16.3/plugins/common/tls"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	_ "google.golang.org/grpc/encoding/gzip" // Register GRPC gzip decoder to support compressed requests
+)
+
+// defaultMaxBodySize is the default maximum request body size, in bytes.
+// if the request body is over this size, we will return an HTTP 413 error.
+// 64 MB
+const defaultMaxBodySize = 64 * 1024 * 1024
+const defaultMaxUndeliveredMessages = 1000
+
+var errNotDelivered = errors.New("metrics could not be delivered")
+
+type OpenTelemetry struct {
+	ServiceAddress         string            `toml:"service_address"`
+	HTTPServiceAddress     string            `toml:"http_service_address"`
+	ReadTimeout            internal.Duration `toml:"read_timeout"`
+	WriteTimeout           internal.Duration `toml:"write_timeout"`
+	MaxBodySize            internal.Size     `toml:"max_body_size"`
+	MaxUndeliveredMessages int               `toml:"max_undelivered_messages"`
+	Log                    telegraf.Logger   `toml:"-"`
+
+	tlsint.ServerConfig
+
+	grpcServer   *grpc.Server
+	grpcListener net.Listener
+	httpServer   *http.Server
+	httpListener net.Listener
+
+	acc    telegraf.TrackingAccumulator
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+
+	undelivered map[telegraf.TrackingID]chan bool
+	sem         chan struct{}
+}
+
+const sampleConfig = `
+  ## Address and port to listen on for OTLP over gRPC.
+  ## Set to an empty string to disable the gRPC receiver.
+  service_address = ":4317"
+
+  ## Address and port to listen on for OTLP over HTTP; requests are accepted
+  ## on the "/v1/metrics" path in protobuf or JSON encoding.
+  ## Leave empty to disable the HTTP receiver.
+  # http_service_address = ":4318"
+
+  ## Maximum duration before timing out read of the request
+  # read_timeout = "10s"
+  ## Maximum duration before timing out write of the response. This should be
+  ## set large enough for the outputs to write the received metrics, as the
+  ## response is only sent after delivery.
+  # write_timeout = "10s"
+
+  ## Maximum allowed request size in bytes.
+  # max_body_size = "64MB"
+
+  ## Maximum number of export requests that have not been written to an
+  ## output.  Further requests block until earlier ones are delivered.
+  ## For best throughput set based on the number of metrics within
+  ## each request and the size of the output's metric_batch_size.
+  # max_undelivered_messages = 1000
+
+  ## Set one or more allowed client CA certificate file names to
+  ## enable mutually authenticated TLS connections
+  # tls_allowed_cacerts = ["/etc/telegraf/clientca.pem"]
+
+  ## Add service certificate and key
+  # tls_cert = "/etc/telegraf/cert.pem"
+  # tls_key = "/etc/telegraf/key.pem"
+`
+
+func (o *OpenTelemetry) SampleConfig() string {
+	return sampleConfig
+}
+
+func (o *OpenTelemetry) Description() string {
+	return "Receive OpenTelemetry (OTLP) metrics over gRPC and HTTP"
+}
+
+func (o *OpenTelemetry) Gather(_ telegraf.Accumulator) error {
+	return nil
+}
+
+// Start starts the OTLP receivers.
+func (o *OpenTelemetry) Start(acc telegraf.Accumulator) error {
+	if o.ServiceAddress == "" && o.HTTPServiceAddress == "" {
+		return errors.New("at least one of service_address or http_service_address must be set")
+	}
+
+	if o.MaxBodySize.Size == 0 {
+		o.MaxBodySize.Size = defaultMaxBodySize
+	}
+	if o.ReadTimeout.Duration < time.Second {
+		o.ReadTimeout.Duration = time.Second * 10
+	}
+	if o.WriteTimeout.Duration < time.Second {
+		o.WriteTimeout.Duration = time.Second * 10
+	}
+	if o.MaxUndeliveredMessages <= 0 {
+		o.MaxUndeliveredMessages = defaultMaxUndeliveredMessages
+	}
+
+	tlsConf, err := o.ServerConfig.TLSConfig()
+	if err != nil {
+		return err
+	}
+
+	o.ctx, o.cancel = context.WithCancel(context.Background())
+	o.acc = acc.WithTracking(o.MaxUndeliveredMessages)
+	o.sem = make(chan struct{}, o.MaxUndeliveredMessages)
+	o.undelivered = make(map[telegraf.TrackingID]chan bool)
+
+	if o.ServiceAddress != "" {
+		o.grpcListener, err = net.Listen("tcp", o.ServiceAddress)
+		if err != nil {
+			o.cancel()
+			return err
+		}
+
+		opts := []grpc.ServerOption{grpc.MaxRecvMsgSize(int(o.MaxBodySize.Size))}
+		if tlsConf != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConf)))
+		}
+		o.grpcServer = grpc.NewServer(opts...)
+		registerMetricsService(o.grpcServer, o)
+		o.Log.Infof("Listening for OTLP/gRPC on %s", o.grpcListener.Addr().String())
+	}
+
+	if o.HTTPServiceAddress != "" {
+		if tlsConf != nil {
+			o.httpListener, err = tls.Listen("tcp", o.HTTPServiceAddress, tlsConf)
+		} else {
+			o.httpListener, err = net.Listen("tcp", o.HTTPServiceAddress)
+		}
+		if err != nil {
+			if o.grpcListener != nil {
+				o.grpcListener.Close()
+			}
+			o.cancel()
+			return err
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc(metricsPath, o.serveMetrics)
+		o.httpServer = &http.Server{
+			Handler:      mux,
+			ReadTimeout:  o.ReadTimeout.Duration,
+			WriteTimeout: o.WriteTimeout.Duration,
+		}
+		o.Log.Infof("Listening for OTLP/HTTP on %s", o.httpListener.Addr().String())
+	}
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		o.receiveDelivered()
+	}()
+
+	if o.grpcServer != nil {
+		o.wg.Add(1)
+		go func() {
+			defer o.wg.Done()
+			if err := o.grpcServer.Serve(o.grpcListener); err != nil {
+				o.Log.Errorf("Serving gRPC: %v", err)
+			}
+		}()
+	}
+
+	if o.httpServer != nil {
+		o.wg.Add(1)
+		go func() {
+			defer o.wg.Done()
+			if err := o.httpServer.Serve(o.httpListener); err != http.ErrServerClosed {
+				o.Log.Errorf("Serving HTTP: %v", err)
+			}
+		}()
+	}
+
+	return nil
+}
+
+// Stop cleans up all resources
+func (o *OpenTelemetry) Stop() {
+	o.cancel()
+	if o.grpcServer != nil {
+		o.grpcServer.Stop()
+	}
+	if o.httpServer != nil {
+		o.httpServer.Shutdown(context.Background())
+	}
+	o.wg.Wait()
+}
+
+// deliver adds the metrics as a tracking group and blocks until they have
+// been written by the outputs or the context is done.
+func (o *OpenTelemetry) deliver(ctx context.Context, metrics []telegraf.Metric) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-o.ctx.Done():
+		return o.ctx.Err()
+	case o.sem <- struct{}{}:
+	}
+
+	ch := make(chan bool, 1)
+	o.mu.Lock()
+	o.undelivered[o.acc.AddTrackingMetricGroup(metrics)] = ch
+	o.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-o.ctx.Done():
+		return o.ctx.Err()
+	case success := <-ch:
+		if !success {
+			return errNotDelivered
+		}
+		return nil
+	}
+}
+
+func (o *OpenTelemetry) receiveDelivered() {
+	for {
+		select {
+		case <-o.ctx.Done():
+			return
+		case info := <-o.acc.Delivered():
+			<-o.sem
+
+			o.mu.Lock()
+			ch, ok := o.undelivered[info.ID()]
+			if !ok {
+				o.mu.Unlock()
+				continue
+			}
+
+			delete(o.undelivered, info.ID())
+			o.mu.Unlock()
+
+			if info.Delivered() {
+				ch <- true
+			} else {
+				ch <- false
+				o.Log.Debug("Metric group failed to process")
+			}
+		}
+	}
+}
+
+func init() {
+	inputs.Add("opentelemetry", func() telegraf.Input {
+		return &OpenTelemetry{
+			ServiceAddress:         ":4317",
+			MaxUndeliveredMessages: defaultMaxUndeliveredMessages,
+		}
+	})
+}
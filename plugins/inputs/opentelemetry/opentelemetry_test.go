@@ -0,0 +1,322 @@
+package opentelemetry
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/agent"
+	"github.com/shanas-swi/telegraf-v1.16.3/models"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/otlp"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func stringAttr(key, value string) *otlp.KeyValue {
+	return &otlp.KeyValue{
+		Key:   key,
+		Value: &otlp.AnyValue{Value: &otlp.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func exportRequest(metrics ...*otlp.Metric) *otlp.ExportMetricsServiceRequest {
+	return &otlp.ExportMetricsServiceRequest{
+		ResourceMetrics: []*otlp.ResourceMetrics{
+			{
+				Resource: &otlp.Resource{
+					Attributes: []*otlp.KeyValue{stringAttr("service.name", "checkout")},
+				},
+				ScopeMetrics: []*otlp.ScopeMetrics{
+					{
+						Scope:   &otlp.InstrumentationScope{Name: "test", Version: "1.0"},
+						Metrics: metrics,
+					},
+				},
+			},
+		},
+	}
+}
+
+var gaugeMetric = &otlp.Metric{
+	Name: "temperature",
+	Data: &otlp.Metric_Gauge{
+		Gauge: &otlp.Gauge{
+			DataPoints: []*otlp.NumberDataPoint{
+				{
+					Attributes:   []*otlp.KeyValue{stringAttr("room", "kitchen")},
+					TimeUnixNano: 1e9,
+					Value:        &otlp.NumberDataPoint_AsDouble{AsDouble: 21.5},
+				},
+			},
+		},
+	},
+}
+
+func TestConvertMetrics(t *testing.T) {
+	min, sum, expSum := 0.1, 4.2, 10.0
+	metrics := convertResourceMetrics(exportRequest(
+		gaugeMetric,
+		&otlp.Metric{
+			Name: "requests",
+			Data: &otlp.Metric_Sum{
+				Sum: &otlp.Sum{
+					IsMonotonic:            true,
+					AggregationTemporality: otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+					DataPoints: []*otlp.NumberDataPoint{
+						{TimeUnixNano: 1e9, Value: &otlp.NumberDataPoint_AsInt{AsInt: 42}},
+					},
+				},
+			},
+		},
+		&otlp.Metric{
+			Name: "queue",
+			Data: &otlp.Metric_Sum{
+				Sum: &otlp.Sum{
+					AggregationTemporality: otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+					DataPoints: []*otlp.NumberDataPoint{
+						{TimeUnixNano: 1e9, Value: &otlp.NumberDataPoint_AsInt{AsInt: -3}},
+					},
+				},
+			},
+		},
+		&otlp.Metric{
+			Name: "latency",
+			Data: &otlp.Metric_Histogram{
+				Histogram: &otlp.Histogram{
+					AggregationTemporality: otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+					DataPoints: []*otlp.HistogramDataPoint{
+						{
+							TimeUnixNano:   1e9,
+							Count:          6,
+							Sum:            &sum,
+							Min:            &min,
+							ExplicitBounds: []float64{0.5, 1},
+							BucketCounts:   []uint64{3, 2, 1},
+						},
+					},
+				},
+			},
+		},
+		&otlp.Metric{
+			Name: "size",
+			Data: &otlp.Metric_ExponentialHistogram{
+				ExponentialHistogram: &otlp.ExponentialHistogram{
+					AggregationTemporality: otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+					DataPoints: []*otlp.ExponentialHistogramDataPoint{
+						{
+							TimeUnixNano: 1e9,
+							Count:        7,
+							Sum:          &expSum,
+							Scale:        0,
+							ZeroCount:    1,
+							Positive: &otlp.ExponentialHistogramDataPoint_Buckets{
+								Offset:       1,
+								BucketCounts: []uint64{2, 3},
+							},
+							Negative: &otlp.ExponentialHistogramDataPoint_Buckets{
+								Offset:       0,
+								BucketCounts: []uint64{1},
+							},
+						},
+					},
+				},
+			},
+		},
+		&otlp.Metric{
+			Name: "rpc",
+			Data: &otlp.Metric_Summary{
+				Summary: &otlp.Summary{
+					DataPoints: []*otlp.SummaryDataPoint{
+						{
+							TimeUnixNano: 1e9,
+							Count:        10,
+							Sum:          5,
+							QuantileValues: []*otlp.SummaryDataPoint_ValueAtQuantile{
+								{Quantile: 0.5, Value: 0.4},
+								{Quantile: 0.99, Value: 1.2},
+							},
+						},
+					},
+				},
+			},
+		},
+	).GetResourceMetrics(), testutil.Logger{})
+
+	tags := func(extra map[string]string) map[string]string {
+		t := map[string]string{
+			"service.name":       "checkout",
+			"otel.scope.name":    "test",
+			"otel.scope.version": "1.0",
+		}
+		for k, v := range extra {
+			t[k] = v
+		}
+		return t
+	}
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("temperature",
+			tags(map[string]string{"room": "kitchen"}),
+			map[string]interface{}{"gauge": 21.5},
+			time.Unix(1, 0), telegraf.Gauge),
+		testutil.MustMetric("requests",
+			tags(map[string]string{"temporality": "cumulative"}),
+			map[string]interface{}{"counter": int64(42)},
+			time.Unix(1, 0), telegraf.Counter),
+		testutil.MustMetric("queue",
+			tags(map[string]string{"temporality": "delta"}),
+			map[string]interface{}{"gauge": int64(-3)},
+			time.Unix(1, 0), telegraf.Gauge),
+		testutil.MustMetric("latency",
+			tags(map[string]string{"temporality": "delta"}),
+			map[string]interface{}{
+				"count": 6.0,
+				"sum":   4.2,
+				"min":   0.1,
+				"0.5":   3.0,
+				"1":     5.0,
+				"+Inf":  6.0,
+			},
+			time.Unix(1, 0), telegraf.Histogram),
+		testutil.MustMetric("size",
+			tags(map[string]string{"temporality": "cumulative"}),
+			map[string]interface{}{
+				"count": 7.0,
+				"sum":   10.0,
+				"-1":    1.0,
+				"0":     2.0,
+				"4":     4.0,
+				"8":     7.0,
+				"+Inf":  7.0,
+			},
+			time.Unix(1, 0), telegraf.Histogram),
+		testutil.MustMetric("rpc",
+			tags(nil),
+			map[string]interface{}{
+				"count": 10.0,
+				"sum":   5.0,
+				"0.5":   0.4,
+				"0.99":  1.2,
+			},
+			time.Unix(1, 0), telegraf.Summary),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+type testMetricMaker struct{}
+
+func (tm *testMetricMaker) Name() string {
+	return "TestPlugin"
+}
+
+func (tm *testMetricMaker) LogName() string {
+	return tm.Name()
+}
+
+func (tm *testMetricMaker) MakeMetric(metric telegraf.Metric) telegraf.Metric {
+	return metric
+}
+
+func (tm *testMetricMaker) Log() telegraf.Logger {
+	return models.NewLogger("test", "test", "")
+}
+
+// startReceiver starts the plugin and a consumer that accepts or rejects
+// every tracked metric.
+func startReceiver(t *testing.T, accept bool) (*OpenTelemetry, chan telegraf.Metric) {
+	o := &OpenTelemetry{
+		ServiceAddress:         "127.0.0.1:0",
+		HTTPServiceAddress:     "127.0.0.1:0",
+		MaxUndeliveredMessages: 10,
+		Log:                    testutil.Logger{},
+	}
+
+	dst := make(chan telegraf.Metric, 10)
+	received := make(chan telegraf.Metric, 10)
+	require.NoError(t, o.Start(agent.NewAccumulator(&testMetricMaker{}, dst)))
+
+	go func() {
+		for m := range dst {
+			received <- m
+			if accept {
+				m.Accept()
+			} else {
+				m.Reject()
+			}
+		}
+	}()
+
+	return o, received
+}
+
+func TestGRPC(t *testing.T) {
+	o, received := startReceiver(t, true)
+	defer o.Stop()
+
+	conn, err := grpc.Dial(o.grpcListener.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client := otlp.NewMetricsServiceClient(conn)
+	_, err = client.Export(ctx, exportRequest(gaugeMetric))
+	require.NoError(t, err)
+
+	m := <-received
+	require.Equal(t, "temperature", m.Name())
+	require.Equal(t, map[string]interface{}{"gauge": 21.5}, m.Fields())
+}
+
+func TestGRPCNotDelivered(t *testing.T) {
+	o, _ := startReceiver(t, false)
+	defer o.Stop()
+
+	conn, err := grpc.Dial(o.grpcListener.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client := otlp.NewMetricsServiceClient(conn)
+	_, err = client.Export(ctx, exportRequest(gaugeMetric))
+	require.Error(t, err)
+}
+
+func TestHTTP(t *testing.T) {
+	o, received := startReceiver(t, true)
+	defer o.Stop()
+
+	url := "http://" + o.httpListener.Addr().String() + metricsPath
+
+	buf, err := proto.Marshal(exportRequest(gaugeMetric))
+	require.NoError(t, err)
+	resp, err := http.Post(url, contentTypeProtobuf, bytes.NewReader(buf))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "temperature", (<-received).Name())
+
+	resp, err = http.Post(url, "text/plain", bytes.NewReader(buf))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	resp, err = http.Post(url, contentTypeProtobuf, bytes.NewReader([]byte("garbage")))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Post(url, contentTypeJSON, bytes.NewReader([]byte(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"up","gauge":{"dataPoints":[{"asInt":"1","timeUnixNano":"1000000000"}]}}]}]}]}`)))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	m := <-received
+	require.Equal(t, "up", m.Name())
+	require.Equal(t, map[string]interface{}{"gauge": int64(1)}, m.Fields())
+}
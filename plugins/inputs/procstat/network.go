@@ -0,0 +1,107 @@
+package procstat
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+)
+
+// Names of the TCP socket states, in the order of their kernel values
+// starting at 1.  See include/net/tcp_states.h in the kernel sources.
+var tcpStateNames = []string{
+	"established",
+	"syn_sent",
+	"syn_recv",
+	"fin_wait1",
+	"fin_wait2",
+	"time_wait",
+	"close",
+	"close_wait",
+	"last_ack",
+	"listen",
+	"closing",
+}
+
+// socketStats are the statistics of the sockets opened by a process.
+type socketStats struct {
+	// Number of TCP sockets by state name
+	tcp map[string]int64
+	// Sum of the unrecovered retransmission timeouts of the TCP sockets
+	tcpRetransmits int64
+	udp            int64
+	listen         map[listenAddress]int64
+}
+
+// socketEntry is a socket of the socket tables of a network namespace.
+type socketEntry struct {
+	protocol    string
+	state       uint8
+	retransmits int64
+	// Local address in the format of the socket tables
+	local string
+}
+
+// socketTable are the sockets of a network namespace by their inode.
+type socketTable map[uint64]*socketEntry
+
+// listenAddress is the local address of a listening TCP socket or of an
+// unconnected UDP socket.
+type listenAddress struct {
+	protocol string
+	address  string
+	port     uint16
+}
+
+func newSocketStats() *socketStats {
+	return &socketStats{
+		tcp:    make(map[string]int64, len(tcpStateNames)),
+		listen: make(map[listenAddress]int64),
+	}
+}
+
+// hostProc returns the path of the proc filesystem.
+func hostProc() string {
+	if path := os.Getenv("HOST_PROC"); path != "" {
+		return path
+	}
+	return "/proc"
+}
+
+// addNetworkMetrics adds the socket statistics of the process to the fields
+// and a procstat_listen metric for each address the process listens on.
+func (p *Procstat) addNetworkMetrics(proc Process, prefix string, fields map[string]interface{}, acc telegraf.Accumulator) {
+	stats, err := gatherSockets(p.procPath, proc.PID(), p.socketTables)
+	if err != nil {
+		// The process may have exited in the meantime
+		if !os.IsNotExist(err) {
+			acc.AddError(fmt.Errorf("collecting network statistics of pid %d failed: %v", proc.PID(), err))
+		}
+		return
+	}
+
+	for _, state := range tcpStateNames {
+		fields[prefix+"tcp_"+state] = stats.tcp[state]
+	}
+	fields[prefix+"tcp_retransmits"] = stats.tcpRetransmits
+	fields[prefix+"udp_socket"] = stats.udp
+
+	for addr, count := range stats.listen {
+		tags := make(map[string]string, len(proc.Tags())+3)
+		for k, v := range proc.Tags() {
+			tags[k] = v
+		}
+		tags["protocol"] = addr.protocol
+		tags["address"] = addr.address
+		tags["port"] = strconv.Itoa(int(addr.port))
+
+		listenFields := map[string]interface{}{
+			"sockets": count,
+		}
+		if _, pidInTags := tags["pid"]; !pidInTags {
+			listenFields["pid"] = int32(proc.PID())
+		}
+		acc.AddFields("procstat_listen", listenFields, tags)
+	}
+}
@@ -0,0 +1,206 @@
+package procstat
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	tcpStateClose  = 0x07
+	tcpStateListen = 0x0A
+)
+
+// gatherSockets collects the statistics of the sockets opened by the
+// process.  The socket inodes of the file descriptors of the process are
+// looked up in the socket tables of the network namespace of the process,
+// the tables are parsed once per namespace and kept in tables.
+func gatherSockets(procPath string, pid PID, tables map[string]socketTable) (*socketStats, error) {
+	pidPath := filepath.Join(procPath, strconv.Itoa(int(pid)))
+
+	inodes, err := socketInodes(filepath.Join(pidPath, "fd"))
+	if err != nil {
+		return nil, err
+	}
+
+	stats := newSocketStats()
+	if len(inodes) == 0 {
+		return stats, nil
+	}
+
+	table, err := namespaceSockets(pidPath, tables)
+	if err != nil {
+		return nil, err
+	}
+	for inode := range inodes {
+		if socket, ok := table[inode]; ok {
+			if err := stats.add(socket); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return stats, nil
+}
+
+// namespaceSockets returns the sockets of the network namespace of the
+// process.  If the namespace can't be determined the tables are parsed
+// without caching them.
+func namespaceSockets(pidPath string, tables map[string]socketTable) (socketTable, error) {
+	namespace, err := os.Readlink(filepath.Join(pidPath, "ns", "net"))
+	if err == nil {
+		if table, ok := tables[namespace]; ok {
+			return table, nil
+		}
+	}
+
+	table := make(socketTable)
+	for _, protocol := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		err := parseSocketTable(filepath.Join(pidPath, "net", protocol), protocol, table)
+		if os.IsNotExist(err) && strings.HasSuffix(protocol, "6") {
+			// IPv6 is disabled
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if namespace != "" {
+		tables[namespace] = table
+	}
+	return table, nil
+}
+
+// add adds a socket of the process to the statistics.
+func (s *socketStats) add(socket *socketEntry) error {
+	listening := false
+	if strings.HasPrefix(socket.protocol, "tcp") {
+		if socket.state < 1 || int(socket.state) > len(tcpStateNames) {
+			return nil
+		}
+		s.tcp[tcpStateNames[socket.state-1]]++
+		s.tcpRetransmits += socket.retransmits
+		listening = socket.state == tcpStateListen
+	} else {
+		s.udp++
+		// Unconnected UDP sockets receive from any address
+		listening = socket.state == tcpStateClose
+	}
+
+	if !listening {
+		return nil
+	}
+	address, port, err := parseSocketAddress(socket.local)
+	if err != nil {
+		return err
+	}
+	if port == 0 {
+		// Not bound
+		return nil
+	}
+	s.listen[listenAddress{protocol: socket.protocol, address: address, port: port}]++
+	return nil
+}
+
+// socketInodes returns the inodes of the sockets in the fd directory of a
+// process.  The links of sockets point to "socket:[<inode>]".
+func socketInodes(fdPath string) (map[uint64]bool, error) {
+	files, err := ioutil.ReadDir(fdPath)
+	if err != nil {
+		return nil, err
+	}
+
+	inodes := make(map[uint64]bool)
+	for _, file := range files {
+		target, err := os.Readlink(filepath.Join(fdPath, file.Name()))
+		if err != nil {
+			// The file descriptor was closed in the meantime
+			continue
+		}
+		if !strings.HasPrefix(target, "socket:[") || !strings.HasSuffix(target, "]") {
+			continue
+		}
+		inode, err := strconv.ParseUint(target[len("socket:["):len(target)-1], 10, 64)
+		if err != nil {
+			continue
+		}
+		inodes[inode] = true
+	}
+	return inodes, nil
+}
+
+// parseSocketTable adds the sockets of a socket table such as
+// /proc/<pid>/net/tcp to the table by their inode.
+//
+// The lines of the tables have the following columns:
+//
+//	sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode ...
+func parseSocketTable(path, protocol string, table socketTable) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	// Skip the header
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		inode, err := strconv.ParseUint(fields[9], 10, 64)
+		if err != nil || inode == 0 {
+			// Sockets without inode, such as in the TIME_WAIT state, do not
+			// belong to a process
+			continue
+		}
+		state, err := strconv.ParseUint(fields[3], 16, 8)
+		if err != nil {
+			return fmt.Errorf("parsing %q failed: invalid state %q", path, fields[3])
+		}
+		retransmits, err := strconv.ParseInt(fields[6], 16, 64)
+		if err != nil {
+			return fmt.Errorf("parsing %q failed: invalid retransmits %q", path, fields[6])
+		}
+		table[inode] = &socketEntry{
+			protocol:    protocol,
+			state:       uint8(state),
+			retransmits: retransmits,
+			local:       fields[1],
+		}
+	}
+	return scanner.Err()
+}
+
+// parseSocketAddress parses an address of the socket tables.  The address is
+// written as hexadecimal 32 bit words in host byte order, which is assumed
+// to be little endian, followed by the port in hexadecimal.
+func parseSocketAddress(s string) (string, uint16, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return "", 0, fmt.Errorf("invalid address %q", s)
+	}
+
+	b, err := hex.DecodeString(parts[0])
+	if err != nil || (len(b) != net.IPv4len && len(b) != net.IPv6len) {
+		return "", 0, fmt.Errorf("invalid address %q", s)
+	}
+	ip := make(net.IP, len(b))
+	for i := 0; i < len(b); i += 4 {
+		ip[i], ip[i+1], ip[i+2], ip[i+3] = b[i+3], b[i+2], b[i+1], b[i]
+	}
+
+	port, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port in address %q", s)
+	}
+	return ip.String(), uint16(port), nil
+}
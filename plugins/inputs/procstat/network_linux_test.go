@@ -0,0 +1,228 @@
+package procstat
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const socketTableHeader = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+
+var socketTables = map[string]string{
+	"tcp": socketTableHeader +
+		"   0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 100 1 0000000000000000 100 0 0 10 0\n" +
+		"   1: 0100007F:1F90 0100007F:D2F0 01 00000000:00000000 00:00000000 00000000  1000        0 101 1 0000000000000000 20 4 30 10 -1\n" +
+		"   2: 0100007F:D2F2 0200000A:0050 01 00000000:00000000 01:00000100 00000003  1000        0 102 1 0000000000000000 20 4 30 10 -1\n" +
+		"   3: 0100007F:1F91 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 999 1 0000000000000000 100 0 0 10 0\n",
+	"tcp6": socketTableHeader +
+		"   0: 00000000000000000000000001000000:1F90 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 103 1 0000000000000000 100 0 0 10 0\n",
+	"udp": "   sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops\n" +
+		"  100: 00000000:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 200 2 0000000000000000 0\n" +
+		"  101: 0100007F:B3A1 0100007F:0035 01 00000000:00000000 00:00000000 00000000     0        0 201 2 0000000000000000 0\n",
+}
+
+const netNamespace = "net:[4026531992]"
+
+// makeProcTree creates a proc filesystem with a process with the given PID
+// and the sockets of socketTables.  IPv6 UDP is disabled.
+func makeProcTree(t *testing.T, pid string) string {
+	dir, err := ioutil.TempDir("", "procstat")
+	require.NoError(t, err)
+	addProcess(t, dir, pid)
+
+	netPath := filepath.Join(dir, pid, "net")
+	require.NoError(t, os.MkdirAll(netPath, 0755))
+	for name, content := range socketTables {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(netPath, name), []byte(content), 0644))
+	}
+	return dir
+}
+
+// addProcess adds a process with the sockets of socketTables to the proc
+// filesystem, the socket tables are only added by makeProcTree.
+func addProcess(t *testing.T, dir, pid string) {
+	nsPath := filepath.Join(dir, pid, "ns")
+	require.NoError(t, os.MkdirAll(nsPath, 0755))
+	require.NoError(t, os.Symlink(netNamespace, filepath.Join(nsPath, "net")))
+
+	fdPath := filepath.Join(dir, pid, "fd")
+	require.NoError(t, os.MkdirAll(fdPath, 0755))
+	links := map[string]string{
+		"0": "/dev/null",
+		"3": "socket:[100]",
+		"4": "socket:[101]",
+		"5": "socket:[102]",
+		"6": "socket:[103]",
+		"7": "socket:[200]",
+		"8": "socket:[201]",
+		"9": "pipe:[300]",
+	}
+	for fd, target := range links {
+		require.NoError(t, os.Symlink(target, filepath.Join(fdPath, fd)))
+	}
+}
+
+func TestGather_NetworkStats(t *testing.T) {
+	procPath := makeProcTree(t, "42")
+	defer os.RemoveAll(procPath)
+
+	var acc testutil.Accumulator
+	p := Procstat{
+		Exe:             exe,
+		PidTag:          true,
+		NetworkStats:    true,
+		createPIDFinder: pidFinder([]PID{pid}, nil),
+		createProcess: func(pid PID) (Process, error) {
+			return &testProc{pid: pid, tags: make(map[string]string)}, nil
+		},
+		procPath: procPath,
+	}
+	require.NoError(t, acc.GatherError(p.Gather))
+
+	procstat, ok := acc.Get("procstat")
+	require.True(t, ok)
+	expected := map[string]int64{
+		"tcp_established": 2,
+		"tcp_syn_sent":    0,
+		"tcp_syn_recv":    0,
+		"tcp_fin_wait1":   0,
+		"tcp_fin_wait2":   0,
+		"tcp_time_wait":   0,
+		"tcp_close":       0,
+		"tcp_close_wait":  0,
+		"tcp_last_ack":    0,
+		"tcp_listen":      2,
+		"tcp_closing":     0,
+		"tcp_retransmits": 3,
+		"udp_socket":      2,
+	}
+	for field, value := range expected {
+		require.Equal(t, value, procstat.Fields[field], field)
+	}
+
+	listen := []struct {
+		protocol string
+		address  string
+		port     string
+	}{
+		{"tcp", "127.0.0.1", "8080"},
+		{"tcp6", "::1", "8080"},
+		{"udp", "0.0.0.0", "53"},
+	}
+	for _, l := range listen {
+		tags := map[string]string{
+			"exe":          exe,
+			"pid":          "42",
+			"process_name": "test_proc",
+			"user":         "testuser",
+			"protocol":     l.protocol,
+			"address":      l.address,
+			"port":         l.port,
+		}
+		acc.AssertContainsTaggedFields(t, "procstat_listen", map[string]interface{}{"sockets": int64(1)}, tags)
+	}
+	require.Equal(t, len(listen), countMeasurement(&acc, "procstat_listen"))
+}
+
+func TestGatherSocketsSharedNamespace(t *testing.T) {
+	procPath := makeProcTree(t, "42")
+	defer os.RemoveAll(procPath)
+	// The socket tables of the namespace are only parsed for the first
+	// process, the second one has none
+	addProcess(t, procPath, "43")
+
+	tables := make(map[string]socketTable)
+	for _, pid := range []PID{42, 43} {
+		stats, err := gatherSockets(procPath, pid, tables)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), stats.tcp["established"])
+		require.Equal(t, int64(3), stats.tcpRetransmits)
+	}
+	require.Len(t, tables, 1)
+	require.Contains(t, tables, netNamespace)
+
+	// Missing IPv4 socket tables are an error, the process exited
+	_, err := gatherSockets(procPath, 43, make(map[string]socketTable))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestGather_NetworkStatsError(t *testing.T) {
+	procPath, err := ioutil.TempDir("", "procstat")
+	require.NoError(t, err)
+	defer os.RemoveAll(procPath)
+
+	// Unreadable file descriptors are reported, processes that exited are not
+	require.NoError(t, os.MkdirAll(filepath.Join(procPath, "42"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(procPath, "42", "fd"), nil, 0644))
+
+	var acc testutil.Accumulator
+	p := Procstat{
+		Exe:             exe,
+		PidTag:          true,
+		NetworkStats:    true,
+		createPIDFinder: pidFinder([]PID{42, 43}, nil),
+		createProcess: func(pid PID) (Process, error) {
+			return &testProc{pid: pid, tags: make(map[string]string)}, nil
+		},
+		procPath: procPath,
+	}
+	require.NoError(t, p.Gather(&acc))
+	require.Len(t, acc.Errors, 1)
+	require.Contains(t, acc.Errors[0].Error(), "pid 42")
+	require.False(t, acc.HasField("procstat", "tcp_established"))
+}
+
+func TestGather_NetworkStatsDisabled(t *testing.T) {
+	procPath := makeProcTree(t, "42")
+	defer os.RemoveAll(procPath)
+
+	var acc testutil.Accumulator
+	p := Procstat{
+		Exe:             exe,
+		createPIDFinder: pidFinder([]PID{pid}, nil),
+		createProcess:   newTestProc,
+		procPath:        procPath,
+	}
+	require.NoError(t, acc.GatherError(p.Gather))
+	require.False(t, acc.HasField("procstat", "tcp_established"))
+	require.False(t, acc.HasMeasurement("procstat_listen"))
+}
+
+func TestParseSocketAddress(t *testing.T) {
+	tests := []struct {
+		input   string
+		address string
+		port    uint16
+	}{
+		{"0100007F:0050", "127.0.0.1", 80},
+		{"00000000:0000", "0.0.0.0", 0},
+		{"0200A8C0:1F90", "192.168.0.2", 8080},
+		{"00000000000000000000000001000000:0016", "::1", 22},
+		{"000080FE00000000FFB3020229831EFE:01BB", "fe80::202:b3ff:fe1e:8329", 443},
+	}
+	for _, tt := range tests {
+		address, port, err := parseSocketAddress(tt.input)
+		require.NoError(t, err)
+		require.Equal(t, tt.address, address)
+		require.Equal(t, tt.port, port)
+	}
+
+	for _, input := range []string{"", "0100007F", "0100007F:", "01007F:0050", "ZZ00007F:0050", "0100007F:10000"} {
+		_, _, err := parseSocketAddress(input)
+		require.Error(t, err, input)
+	}
+}
+
+func countMeasurement(acc *testutil.Accumulator, measurement string) int {
+	count := 0
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == measurement {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package procstat
+
+import "errors"
+
+func gatherSockets(procPath string, pid PID, tables map[string]socketTable) (*socketStats, error) {
+	return nil, errors.New("network statistics are only available on Linux")
+}
@@ -2,10 +2,12 @@ package procstat
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"time"
 
@@ -35,11 +37,17 @@ type Procstat struct {
 	PidTag      bool
 	WinService  string `toml:"win_service"`
 
+	NetworkStats bool `toml:"network_stats"`
+
 	finder PIDFinder
 
 	createPIDFinder func() (PIDFinder, error)
 	procs           map[PID]Process
 	createProcess   func(PID) (Process, error)
+	procPath        string
+
+	// Socket tables of the network namespaces, valid during a gather
+	socketTables map[string]socketTable
 }
 
 var sampleConfig = `
@@ -77,6 +85,11 @@ var sampleConfig = `
   ## when processes have a short lifetime.
   # pid_tag = false
 
+  ## Collect the socket statistics of the processes from /proc/<pid>/net, the
+  ## socket states, retransmissions and listening addresses.  Only available
+  ## on Linux and requires permission to read /proc/<pid>/fd.
+  # network_stats = false
+
   ## Method to use when finding process IDs.  Can be one of 'pgrep', or
   ## 'native'.  The pgrep finder calls the pgrep executable in the PATH while
   ## the native finder performs the search directly in a manor dependent on the
@@ -92,6 +105,13 @@ func (_ *Procstat) Description() string {
 	return "Monitor process cpu and memory usage"
 }
 
+func (p *Procstat) Init() error {
+	if p.NetworkStats && runtime.GOOS != "linux" {
+		return errors.New("network_stats is only supported on Linux")
+	}
+	return nil
+}
+
 func (p *Procstat) Gather(acc telegraf.Accumulator) error {
 	if p.createPIDFinder == nil {
 		switch p.PidFinder {
@@ -108,6 +128,13 @@ func (p *Procstat) Gather(acc telegraf.Accumulator) error {
 	if p.createProcess == nil {
 		p.createProcess = defaultProcess
 	}
+	if p.procPath == "" {
+		p.procPath = hostProc()
+	}
+	if p.NetworkStats {
+		p.socketTables = make(map[string]socketTable)
+		defer func() { p.socketTables = nil }()
+	}
 
 	pids, tags, err := p.findPids(acc)
 	if err != nil {
@@ -297,6 +324,10 @@ func (p *Procstat) addMetric(proc Process, acc telegraf.Accumulator) {
 		}
 	}
 
+	if p.NetworkStats {
+		p.addNetworkMetrics(proc, prefix, fields, acc)
+	}
+
 	acc.AddFields("procstat", fields, proc.Tags())
 }
 
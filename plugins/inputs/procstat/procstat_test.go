@@ -167,6 +167,15 @@ func (p *testProc) RlimitUsage(gatherUsage bool) ([]process.RlimitStat, error) {
 var pid PID = PID(42)
 var exe string = "foo"
 
+func TestInit_NetworkStats(t *testing.T) {
+	p := Procstat{NetworkStats: true}
+	if runtime.GOOS == "linux" {
+		require.NoError(t, p.Init())
+	} else {
+		require.Error(t, p.Init())
+	}
+}
+
 func TestGather_CreateProcessErrorOk(t *testing.T) {
 	var acc testutil.Accumulator
 
@@ -0,0 +1,176 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3/internal"
+)
+
+// ConsulConfig configures the discovery of targets in a Consul catalog.
+type ConsulConfig struct {
+	Enabled       bool              `toml:"enabled"`
+	Agent         string            `toml:"agent"`
+	Token         string            `toml:"token"`
+	Datacenter    string            `toml:"datacenter"`
+	QueryInterval internal.Duration `toml:"query_interval"`
+	Queries       []*ConsulQuery    `toml:"query"`
+}
+
+// ConsulQuery selects the instances of a service registered in the catalog.
+type ConsulQuery struct {
+	ServiceName string `toml:"name"`
+	ServiceTag  string `toml:"tag"`
+	Scheme      string `toml:"scheme"`
+	Path        string `toml:"path"`
+}
+
+// consulCatalogService is an entry of the /v1/catalog/service response.
+type consulCatalogService struct {
+	Node           string
+	Address        string
+	Datacenter     string
+	ServiceName    string
+	ServiceAddress string
+	ServicePort    int
+	ServiceMeta    map[string]string
+}
+
+func (c *ConsulConfig) init() error {
+	if c.Agent == "" {
+		c.Agent = "http://127.0.0.1:8500"
+	}
+	if _, err := url.Parse(c.Agent); err != nil {
+		return fmt.Errorf("invalid consul agent %q: %v", c.Agent, err)
+	}
+
+	for _, q := range c.Queries {
+		if q.ServiceName == "" {
+			return fmt.Errorf("consul query without service name")
+		}
+		if q.Scheme == "" {
+			q.Scheme = "http"
+		}
+		if q.Path == "" {
+			q.Path = "/metrics"
+		} else if !strings.HasPrefix(q.Path, "/") {
+			q.Path = "/" + q.Path
+		}
+	}
+	return nil
+}
+
+func (p *Prometheus) startConsul(ctx context.Context) {
+	client := &http.Client{Timeout: p.ResponseTimeout.Duration}
+	p.refreshConsul(ctx, client)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(p.ConsulConfig.QueryInterval.Duration)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.refreshConsul(ctx, client)
+			}
+		}
+	}()
+}
+
+// refreshConsul runs all catalog queries and replaces the discovered targets.
+// If a query fails the previous targets of all queries are kept.
+func (p *Prometheus) refreshConsul(ctx context.Context, client *http.Client) {
+	targets := map[string]URLAndAddress{}
+	for _, q := range p.ConsulConfig.Queries {
+		services, err := p.queryConsul(ctx, client, q)
+		if err != nil {
+			p.Log.Errorf("Unable to query consul catalog: %s", err.Error())
+			return
+		}
+
+		for _, service := range services {
+			address := service.ServiceAddress
+			if address == "" {
+				address = service.Address
+			}
+			u := &url.URL{
+				Scheme: q.Scheme,
+				Host:   net.JoinHostPort(address, strconv.Itoa(service.ServicePort)),
+				Path:   q.Path,
+			}
+
+			tags := map[string]string{
+				"consul_service": service.ServiceName,
+				"consul_node":    service.Node,
+			}
+			if service.Datacenter != "" {
+				tags["consul_datacenter"] = service.Datacenter
+			}
+			for k, v := range service.ServiceMeta {
+				tags[k] = v
+			}
+
+			targets[u.String()] = URLAndAddress{
+				URL:         u,
+				OriginalURL: u,
+				Tags:        tags,
+			}
+		}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.consulURLs = targets
+}
+
+func (p *Prometheus) queryConsul(ctx context.Context, client *http.Client, q *ConsulQuery) ([]consulCatalogService, error) {
+	u, err := url.Parse(p.ConsulConfig.Agent)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/v1/catalog/service/" + url.PathEscape(q.ServiceName)
+
+	params := url.Values{}
+	if q.ServiceTag != "" {
+		params.Set("tag", q.ServiceTag)
+	}
+	if p.ConsulConfig.Datacenter != "" {
+		params.Set("dc", p.ConsulConfig.Datacenter)
+	}
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if p.ConsulConfig.Token != "" {
+		req.Header.Set("X-Consul-Token", p.ConsulConfig.Token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned HTTP status %s", u.String(), resp.Status)
+	}
+
+	var services []consulCatalogService
+	if err := json.NewDecoder(resp.Body).Decode(&services); err != nil {
+		return nil, fmt.Errorf("error decoding consul response: %s", err)
+	}
+	return services, nil
+}
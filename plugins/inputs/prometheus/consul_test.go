@@ -0,0 +1,114 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3/internal"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsulDiscovery(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/custom", r.URL.Path)
+		fmt.Fprint(w, sampleTextFormat)
+	}))
+	defer target.Close()
+
+	u, err := url.Parse(target.URL)
+	require.NoError(t, err)
+	host, port, err := net.SplitHostPort(u.Host)
+	require.NoError(t, err)
+
+	consul := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/catalog/service/exporter", r.URL.Path)
+		require.Equal(t, "prometheus", r.URL.Query().Get("tag"))
+		require.Equal(t, "dc2", r.URL.Query().Get("dc"))
+		require.Equal(t, "secret", r.Header.Get("X-Consul-Token"))
+		fmt.Fprintf(w, `[{
+			"Node": "node1",
+			"Address": "10.0.0.1",
+			"Datacenter": "dc2",
+			"ServiceName": "exporter",
+			"ServiceAddress": %q,
+			"ServicePort": %s,
+			"ServiceMeta": {"team": "ops"}
+		}]`, host, port)
+	}))
+	defer consul.Close()
+
+	p := &Prometheus{
+		Log:             testutil.Logger{},
+		MetricVersion:   2,
+		URLTag:          "url",
+		ResponseTimeout: internal.Duration{Duration: 5 * time.Second},
+		ConsulConfig: ConsulConfig{
+			Enabled:       true,
+			Agent:         consul.URL,
+			Token:         "secret",
+			Datacenter:    "dc2",
+			QueryInterval: internal.Duration{Duration: time.Minute},
+			Queries: []*ConsulQuery{
+				{ServiceName: "exporter", ServiceTag: "prometheus", Path: "custom"},
+			},
+		},
+	}
+	require.NoError(t, p.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, p.Start(&acc))
+	defer p.Stop()
+
+	require.NoError(t, p.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	require.True(t, acc.HasFloatField("prometheus", "go_goroutines"))
+	expected := map[string]string{
+		"consul_service":    "exporter",
+		"consul_node":       "node1",
+		"consul_datacenter": "dc2",
+		"team":              "ops",
+	}
+	for k, v := range expected {
+		require.Equal(t, v, acc.TagValue("prometheus", k))
+	}
+	require.Equal(t, target.URL+"/custom", acc.TagValue("prometheus", "url"))
+}
+
+func TestConsulDiscoveryError(t *testing.T) {
+	consul := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer consul.Close()
+
+	p := &Prometheus{
+		Log: testutil.Logger{},
+		ConsulConfig: ConsulConfig{
+			Enabled: true,
+			Agent:   consul.URL,
+			Queries: []*ConsulQuery{{ServiceName: "exporter"}},
+		},
+		consulURLs: map[string]URLAndAddress{
+			"http://10.0.0.1:9100/metrics": {},
+		},
+	}
+	require.NoError(t, p.ConsulConfig.init())
+
+	// Previously discovered targets are kept if the catalog is unavailable
+	p.refreshConsul(context.Background(), &http.Client{})
+	urls, err := p.GetAllURLs()
+	require.NoError(t, err)
+	require.Contains(t, urls, "http://10.0.0.1:9100/metrics")
+}
+
+func TestConsulInitError(t *testing.T) {
+	c := ConsulConfig{Queries: []*ConsulQuery{{}}}
+	require.Error(t, c.init())
+}
@@ -0,0 +1,161 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+)
+
+// fileSDGroup is a target group in the Prometheus file_sd format.
+type fileSDGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// fileSDFile caches the targets of a discovery file until it is modified.
+type fileSDFile struct {
+	modTime time.Time
+	size    int64
+	targets map[string]URLAndAddress
+}
+
+func (p *Prometheus) startFileSD(ctx context.Context) {
+	p.refreshFileSD()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(p.FileSDRefreshInterval.Duration)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.refreshFileSD()
+			}
+		}
+	}()
+}
+
+// refreshFileSD expands the configured file patterns and reloads all files
+// which are new or were modified since the last refresh.
+func (p *Prometheus) refreshFileSD() {
+	if p.fileSDFiles == nil {
+		p.fileSDFiles = map[string]*fileSDFile{}
+	}
+
+	files := map[string]*fileSDFile{}
+	for _, pattern := range p.FileSDFiles {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			p.Log.Errorf("Invalid file_sd pattern %q: %s", pattern, err.Error())
+			continue
+		}
+		for _, filename := range matches {
+			if _, ok := files[filename]; ok {
+				continue
+			}
+
+			info, err := os.Stat(filename)
+			if err != nil {
+				p.Log.Errorf("Could not stat %q: %s", filename, err.Error())
+				continue
+			}
+			if info.IsDir() {
+				continue
+			}
+
+			cached, ok := p.fileSDFiles[filename]
+			if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+				files[filename] = cached
+				continue
+			}
+
+			targets, err := readFileSD(filename)
+			if err != nil {
+				p.Log.Errorf("Could not read file_sd targets: %s", err.Error())
+				// Keep scraping the previous targets of a file which is
+				// temporarily broken, e.g. while being written.
+				if ok {
+					files[filename] = cached
+				}
+				continue
+			}
+			p.Log.Debugf("Loaded %d targets from %q", len(targets), filename)
+			files[filename] = &fileSDFile{
+				modTime: info.ModTime(),
+				size:    info.Size(),
+				targets: targets,
+			}
+		}
+	}
+	p.fileSDFiles = files
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.fileSDURLs = map[string]URLAndAddress{}
+	for _, file := range files {
+		for k, v := range file.targets {
+			p.fileSDURLs[k] = v
+		}
+	}
+}
+
+// readFileSD parses a file in the Prometheus file_sd format.  The format is
+// the same for JSON and YAML files; since JSON is a subset of YAML both are
+// parsed as YAML.
+func readFileSD(filename string) (map[string]URLAndAddress, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []fileSDGroup
+	if err := yaml.Unmarshal(data, &groups); err != nil {
+		return nil, fmt.Errorf("parsing %q failed: %v", filename, err)
+	}
+
+	targets := map[string]URLAndAddress{}
+	for _, group := range groups {
+		scheme := group.Labels["__scheme__"]
+		if scheme == "" {
+			scheme = "http"
+		}
+		path := group.Labels["__metrics_path__"]
+		if path == "" {
+			path = "/metrics"
+		} else if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+
+		// Labels starting with a double underscore are reserved for
+		// internal use and are not added as tags.
+		tags := map[string]string{}
+		for k, v := range group.Labels {
+			if !strings.HasPrefix(k, "__") {
+				tags[k] = v
+			}
+		}
+
+		for _, target := range group.Targets {
+			u, err := url.Parse(scheme + "://" + target + path)
+			if err != nil || u.Host != target {
+				return nil, fmt.Errorf("invalid target %q in %q", target, filename)
+			}
+			targets[u.String()] = URLAndAddress{
+				URL:         u,
+				OriginalURL: u,
+				Tags:        tags,
+			}
+		}
+	}
+	return targets, nil
+}
@@ -0,0 +1,91 @@
+package prometheus
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const fileSDJSON = `
+[
+  {
+    "targets": ["10.0.0.1:9100", "10.0.0.2:9100"],
+    "labels": {"env": "prod", "__metrics_path__": "/custom"}
+  }
+]
+`
+
+const fileSDYAML = `
+- targets:
+    - 10.0.0.3:9100
+  labels:
+    env: dev
+    __scheme__: https
+`
+
+func TestFileSDReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file_sd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	jsonFile := filepath.Join(dir, "targets.json")
+	require.NoError(t, ioutil.WriteFile(jsonFile, []byte(fileSDJSON), 0644))
+
+	p := &Prometheus{
+		Log:         testutil.Logger{},
+		FileSDFiles: []string{filepath.Join(dir, "*")},
+	}
+	p.refreshFileSD()
+
+	urls, err := p.GetAllURLs()
+	require.NoError(t, err)
+	require.Len(t, urls, 2)
+	for _, u := range []string{"http://10.0.0.1:9100/custom", "http://10.0.0.2:9100/custom"} {
+		require.Contains(t, urls, u)
+		require.Equal(t, map[string]string{"env": "prod"}, urls[u].Tags)
+	}
+
+	// A new file is picked up and a modified file is reloaded
+	yamlFile := filepath.Join(dir, "targets.yml")
+	require.NoError(t, ioutil.WriteFile(yamlFile, []byte(fileSDYAML), 0644))
+	require.NoError(t, ioutil.WriteFile(jsonFile, []byte(`[{"targets": ["10.0.0.1:9100"]}]`), 0644))
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(jsonFile, future, future))
+	p.refreshFileSD()
+
+	urls, err = p.GetAllURLs()
+	require.NoError(t, err)
+	require.Len(t, urls, 2)
+	require.Contains(t, urls, "http://10.0.0.1:9100/metrics")
+	require.Empty(t, urls["http://10.0.0.1:9100/metrics"].Tags)
+	require.Contains(t, urls, "https://10.0.0.3:9100/metrics")
+	require.Equal(t, map[string]string{"env": "dev"}, urls["https://10.0.0.3:9100/metrics"].Tags)
+
+	// Targets of a broken file are kept, those of removed files are dropped
+	require.NoError(t, ioutil.WriteFile(yamlFile, []byte("{"), 0644))
+	require.NoError(t, os.Chtimes(yamlFile, future, future))
+	require.NoError(t, os.Remove(jsonFile))
+	p.refreshFileSD()
+
+	urls, err = p.GetAllURLs()
+	require.NoError(t, err)
+	require.Len(t, urls, 1)
+	require.Contains(t, urls, "https://10.0.0.3:9100/metrics")
+}
+
+func TestFileSDInvalidTarget(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file_sd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "targets.json")
+	require.NoError(t, ioutil.WriteFile(filename, []byte(`[{"targets": ["10.0.0.1:9100/path"]}]`), 0644))
+
+	_, err = readFileSD(filename)
+	require.Error(t, err)
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/shanas-swi/telegraf-v1.16.3"
 	"github.com/shanas-swi/telegraf-v1.16.3/internal"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/auth"
 	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/tls"
 	"github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs"
 )
@@ -35,6 +36,13 @@ type Prometheus struct {
 	// Field Selector/s for Kubernetes
 	KubernetesFieldSelector string `toml:"kubernetes_field_selector"`
 
+	// Files containing targets in the Prometheus file_sd format
+	FileSDFiles           []string          `toml:"file_sd_files"`
+	FileSDRefreshInterval internal.Duration `toml:"file_sd_refresh_interval"`
+
+	// Consul catalog service discovery
+	ConsulConfig ConsulConfig `toml:"consul"`
+
 	// Bearer Token authorization file path
 	BearerToken       string `toml:"bearer_token"`
 	BearerTokenString string `toml:"bearer_token_string"`
@@ -50,16 +58,21 @@ type Prometheus struct {
 	URLTag string `toml:"url_tag"`
 
 	tls.ClientConfig
+	auth.HTTPClientConfig
 
 	Log telegraf.Logger
 
 	client *http.Client
+	auth   []auth.HeaderProvider
 
 	// Should we scrape Kubernetes services for prometheus annotations
 	MonitorPods    bool   `toml:"monitor_kubernetes_pods"`
 	PodNamespace   string `toml:"monitor_kubernetes_pods_namespace"`
 	lock           sync.Mutex
 	kubernetesPods map[string]URLAndAddress
+	fileSDFiles    map[string]*fileSDFile
+	fileSDURLs     map[string]URLAndAddress
+	consulURLs     map[string]URLAndAddress
 	cancel         context.CancelFunc
 	wg             sync.WaitGroup
 }
@@ -102,6 +115,31 @@ var sampleConfig = `
   # eg. To scrape pods on a specific node
   # kubernetes_field_selector = "spec.nodeName=$HOSTNAME"
 
+  ## Files containing targets in the Prometheus file_sd format, as JSON or
+  ## YAML.  Glob patterns are supported and files are reloaded when they
+  ## change.  Target labels are added as tags.
+  # file_sd_files = ["/etc/telegraf/targets/*.json"]
+  ## Interval at which the files are checked for changes.
+  # file_sd_refresh_interval = "1m"
+
+  ## Scrape the instances of services registered in a Consul catalog.
+  # [inputs.prometheus.consul]
+  #   enabled = true
+  #   agent = "http://localhost:8500"
+  #   query_interval = "1m"
+  ## ACL token used to access the catalog.
+  #   token = ""
+  ## Datacenter to query, defaults to the datacenter of the agent.
+  #   datacenter = ""
+
+  ## Each query selects the instances of a service, optionally filtered by
+  ## a service tag.
+  #   [[inputs.prometheus.consul.query]]
+  #     name = "node-exporter"
+  #     tag = "prometheus"
+  #     scheme = "http"
+  #     path = "/metrics"
+
   ## Use bearer token for authorization. ('bearer_token' takes priority)
   # bearer_token = "/path/to/bearer/token"
   ## OR
@@ -112,6 +150,19 @@ var sampleConfig = `
   # username = ""
   # password = ""
 
+  ## Optional OAuth2 Client Credentials Grant
+  # client_id = "clientid"
+  # client_secret = "secret"
+  # token_url = "https://indentityprovider/oauth2/v1/token"
+  # scopes = ["urn:opc:idm:__myscopes__"]
+
+  ## Optional file with a bearer token, re-read whenever it changes
+  # bearer_token_file = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+  ## Optional headers with their value read from a file, re-read whenever it
+  ## changes
+  # header_files = {"X-API-Key" = "/etc/telegraf/api-key"}
+
   ## Specify timeout duration for slower prometheus clients (default is 3s)
   # response_timeout = "3s"
 
@@ -136,6 +187,19 @@ func (p *Prometheus) Init() error {
 		p.Log.Warnf("Use of deprecated configuration: 'metric_version = 1'; please update to 'metric_version = 2'")
 	}
 
+	if len(p.FileSDFiles) > 0 && p.FileSDRefreshInterval.Duration <= 0 {
+		return errors.New("file_sd_refresh_interval must be positive")
+	}
+
+	if p.ConsulConfig.Enabled {
+		if p.ConsulConfig.QueryInterval.Duration <= 0 {
+			return errors.New("consul query_interval must be positive")
+		}
+		if err := p.ConsulConfig.init(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -184,6 +248,13 @@ func (p *Prometheus) GetAllURLs() (map[string]URLAndAddress, error) {
 	for k, v := range p.kubernetesPods {
 		allURLs[k] = v
 	}
+	// add the targets of the file and consul service discovery
+	for k, v := range p.fileSDURLs {
+		allURLs[k] = v
+	}
+	for k, v := range p.consulURLs {
+		allURLs[k] = v
+	}
 
 	for _, service := range p.KubernetesServices {
 		URL, err := url.Parse(service)
@@ -244,12 +315,26 @@ func (p *Prometheus) createHTTPClient() (*http.Client, error) {
 		return nil, err
 	}
 
+	var transport http.RoundTripper = &http.Transport{
+		TLSClientConfig:   tlsCfg,
+		DisableKeepAlives: true,
+	}
+
+	// The providers are shared with the clients of unix socket URLs
+	p.auth, err = p.HTTPClientConfig.Providers(&http.Client{
+		Transport: transport,
+		Timeout:   p.ResponseTimeout.Duration,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(p.auth) > 0 {
+		transport = &auth.Transport{Base: transport, Providers: p.auth}
+	}
+
 	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig:   tlsCfg,
-			DisableKeepAlives: true,
-		},
-		Timeout: p.ResponseTimeout.Duration,
+		Transport: transport,
+		Timeout:   p.ResponseTimeout.Duration,
 	}
 
 	return client, nil
@@ -273,16 +358,20 @@ func (p *Prometheus) gatherURL(u URLAndAddress, acc telegraf.Accumulator) error
 
 		// ignore error because it's been handled before getting here
 		tlsCfg, _ := p.ClientConfig.TLSConfig()
-		uClient = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig:   tlsCfg,
-				DisableKeepAlives: true,
-				Dial: func(network, addr string) (net.Conn, error) {
-					c, err := net.Dial("unix", u.URL.Path)
-					return c, err
-				},
+		var transport http.RoundTripper = &http.Transport{
+			TLSClientConfig:   tlsCfg,
+			DisableKeepAlives: true,
+			Dial: func(network, addr string) (net.Conn, error) {
+				c, err := net.Dial("unix", u.URL.Path)
+				return c, err
 			},
-			Timeout: p.ResponseTimeout.Duration,
+		}
+		if len(p.auth) > 0 {
+			transport = &auth.Transport{Base: transport, Providers: p.auth}
+		}
+		uClient = &http.Client{
+			Transport: transport,
+			Timeout:   p.ResponseTimeout.Duration,
 		}
 	} else {
 		if u.URL.Path == "" {
@@ -370,18 +459,26 @@ func (p *Prometheus) gatherURL(u URLAndAddress, acc telegraf.Accumulator) error
 	return nil
 }
 
-// Start will start the Kubernetes scraping if enabled in the configuration
+// Start will start the service discovery and the Kubernetes scraping if
+// enabled in the configuration
 func (p *Prometheus) Start(a telegraf.Accumulator) error {
+	var ctx context.Context
+	ctx, p.cancel = context.WithCancel(context.Background())
+
+	if len(p.FileSDFiles) > 0 {
+		p.startFileSD(ctx)
+	}
+	if p.ConsulConfig.Enabled {
+		p.startConsul(ctx)
+	}
 	if p.MonitorPods {
-		var ctx context.Context
-		ctx, p.cancel = context.WithCancel(context.Background())
 		return p.start(ctx)
 	}
 	return nil
 }
 
 func (p *Prometheus) Stop() {
-	if p.MonitorPods {
+	if p.cancel != nil {
 		p.cancel()
 	}
 	p.wg.Wait()
@@ -390,9 +487,13 @@ func (p *Prometheus) Stop() {
 func init() {
 	inputs.Add("prometheus", func() telegraf.Input {
 		return &Prometheus{
-			ResponseTimeout: internal.Duration{Duration: time.Second * 3},
-			kubernetesPods:  map[string]URLAndAddress{},
-			URLTag:          "url",
+			ResponseTimeout:       internal.Duration{Duration: time.Second * 3},
+			FileSDRefreshInterval: internal.Duration{Duration: time.Minute},
+			ConsulConfig: ConsulConfig{
+				QueryInterval: internal.Duration{Duration: time.Minute},
+			},
+			kubernetesPods: map[string]URLAndAddress{},
+			URLTag:         "url",
 		}
 	})
 }
@@ -1,10 +1,8 @@
-//go:generate go run -tags generate snmp_mocks_generate.go
 package snmp
 
 import (
 	"fmt"
 	"net"
-	"os/exec"
 	"sync"
 	"testing"
 	"time"
@@ -84,6 +82,13 @@ var tsc = &testSNMPConnection{
 	},
 }
 
+func init() {
+	// Load the MIB modules used for translating the test OIDs.
+	if err := snmp.LoadMibsFromPath([]string{"testdata/mibs"}, testutil.Logger{}); err != nil {
+		panic(err)
+	}
+}
+
 func TestSampleConfig(t *testing.T) {
 	conf := inputs.Inputs["snmp"]()
 	err := toml.Unmarshal([]byte(conf.SampleConfig()), conf)
@@ -98,6 +103,7 @@ func TestSampleConfig(t *testing.T) {
 			Community:      "public",
 			MaxRepetitions: 10,
 			Retries:        3,
+			Path:           []string{"/usr/share/snmp/mibs"},
 		},
 		Name: "snmp",
 	}
@@ -135,6 +141,14 @@ func TestFieldInit(t *testing.T) {
 		}
 		assert.Equal(t, txl.expectedOid, f.Oid, "inputOid='%s' inputName='%s' inputConversion='%s'", txl.inputOid, txl.inputName, txl.inputConversion)
 		assert.Equal(t, txl.expectedName, f.Name, "inputOid='%s' inputName='%s' inputConversion='%s'", txl.inputOid, txl.inputName, txl.inputConversion)
+		assert.Equal(t, txl.expectedConversion, f.Conversion, "inputOid='%s' inputName='%s' inputConversion='%s'", txl.inputOid, txl.inputName, txl.inputConversion)
+	}
+}
+
+func TestFieldInit_unknown(t *testing.T) {
+	for _, oid := range []string{"UNKNOWN-MIB::foo", "TEST::foo", ".iso.foo.1"} {
+		f := Field{Oid: oid}
+		require.Error(t, f.init(), "inputOid='%s'", oid)
 	}
 }
 
@@ -159,6 +173,18 @@ func TestTableInit(t *testing.T) {
 	assert.Contains(t, tbl.Fields, Field{Oid: ".1.0.0.0.1.4", Name: "description", IsTag: true, initialized: true})
 }
 
+func TestTableInit_notAccessible(t *testing.T) {
+	tbl := Table{Oid: "TCP-MIB::tcpConnectionTable"}
+	err := tbl.Init()
+	require.NoError(t, err)
+
+	assert.Equal(t, "tcpConnectionTable", tbl.Name)
+	assert.Equal(t, []Field{
+		{Oid: ".1.3.6.1.2.1.6.19.1.7", Name: "tcpConnectionState", initialized: true},
+		{Oid: ".1.3.6.1.2.1.6.19.1.8", Name: "tcpConnectionProcess", initialized: true},
+	}, tbl.Fields)
+}
+
 func TestSnmpInit(t *testing.T) {
 	s := &Snmp{
 		Tables: []Table{
@@ -186,12 +212,7 @@ func TestSnmpInit(t *testing.T) {
 }
 
 func TestSnmpInit_noTranslate(t *testing.T) {
-	// override execCommand so it returns exec.ErrNotFound
-	defer func(ec func(string, ...string) *exec.Cmd) { execCommand = ec }(execCommand)
-	execCommand = func(_ string, _ ...string) *exec.Cmd {
-		return exec.Command("snmptranslateExecErrNotFound")
-	}
-
+	// OIDs not defined in any MIB module are used as they are
 	s := &Snmp{
 		Fields: []Field{
 			{Oid: ".1.1.1.1", Name: "one", IsTag: true},
@@ -0,0 +1,7 @@
+package sql
+
+import (
+	// Register the pure-Go database drivers available on all platforms.
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v4/stdlib"
+)
@@ -0,0 +1,10 @@
+//go:build linux && (386 || amd64 || arm || arm64)
+// +build linux
+// +build 386 amd64 arm arm64
+
+package sql
+
+import (
+	// Register the SQLite driver on the platforms supported by modernc.org/sqlite.
+	_ "modernc.org/sqlite"
+)
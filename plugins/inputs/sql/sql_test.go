@@ -0,0 +1,151 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitError(t *testing.T) {
+	tests := []struct {
+		name   string
+		plugin *SQL
+	}{
+		{
+			name:   "missing driver",
+			plugin: &SQL{Dsn: "foo", Queries: []Query{{Query: "SELECT 1"}}},
+		},
+		{
+			name:   "missing dsn",
+			plugin: &SQL{Driver: "mysql", Queries: []Query{{Query: "SELECT 1"}}},
+		},
+		{
+			name:   "unknown driver",
+			plugin: &SQL{Driver: "foo", Dsn: "foo", Queries: []Query{{Query: "SELECT 1"}}},
+		},
+		{
+			name:   "no query",
+			plugin: &SQL{Driver: "mysql", Dsn: "foo"},
+		},
+		{
+			name:   "empty query",
+			plugin: &SQL{Driver: "mysql", Dsn: "foo", Queries: []Query{{}}},
+		},
+		{
+			name:   "query and script",
+			plugin: &SQL{Driver: "mysql", Dsn: "foo", Queries: []Query{{Query: "SELECT 1", Script: "testdata/query.sql"}}},
+		},
+		{
+			name:   "missing script",
+			plugin: &SQL{Driver: "mysql", Dsn: "foo", Queries: []Query{{Script: "testdata/nonexistent.sql"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, tt.plugin.Init())
+		})
+	}
+}
+
+func TestInitDriverAlias(t *testing.T) {
+	plugin := &SQL{
+		Driver:  "postgres",
+		Dsn:     "postgres://localhost/test",
+		Queries: []Query{{Script: "testdata/query.sql"}},
+	}
+	require.NoError(t, plugin.Init())
+	require.Equal(t, "pgx", plugin.driverName)
+	require.Equal(t, "SELECT name, value FROM metrics\n", plugin.Queries[0].Query)
+	require.Equal(t, "sql", plugin.Queries[0].Measurement)
+}
+
+func TestParse(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	q := Query{
+		Query:             "SELECT 1",
+		TimeColumn:        "ts",
+		TagColumns:        []string{"host", "region"},
+		FieldColumns:      []string{"*"},
+		FieldColumnsFloat: []string{"load*"},
+		FieldColumnsInt:   []string{"count"},
+		FieldColumnsUint:  []string{"bytes"},
+		FieldColumnsBool:  []string{"enabled"},
+	}
+	require.NoError(t, q.init())
+
+	columns := []string{"host", "region", "ts", "load1", "count", "bytes", "enabled", "note", "empty"}
+	values := []interface{}{
+		[]byte("server01"),
+		int64(3),
+		int64(1600000060),
+		"0.25",
+		float64(42.7),
+		int64(1024),
+		int64(1),
+		[]byte("ok"),
+		nil,
+	}
+
+	tags, fields, timestamp, err := q.parse(columns, values, now)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"host": "server01", "region": "3"}, tags)
+	require.Equal(t, map[string]interface{}{
+		"load1":   0.25,
+		"count":   int64(42),
+		"bytes":   uint64(1024),
+		"enabled": true,
+		"note":    "ok",
+	}, fields)
+	require.Equal(t, time.Unix(1600000060, 0).UTC(), timestamp)
+}
+
+func TestParseTimeFormat(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	ts := time.Date(2020, 9, 13, 12, 26, 40, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		format   string
+		value    interface{}
+		expected time.Time
+	}{
+		{
+			name:     "native",
+			value:    ts,
+			expected: ts,
+		},
+		{
+			name:     "unix_ms",
+			format:   "unix_ms",
+			value:    int64(1600000000123),
+			expected: time.Unix(1600000000, 123000000).UTC(),
+		},
+		{
+			name:     "layout",
+			format:   "2006-01-02 15:04:05",
+			value:    []byte("2020-09-13 12:26:40"),
+			expected: ts,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := Query{Query: "SELECT 1", TimeColumn: "time", TimeFormat: tt.format}
+			require.NoError(t, q.init())
+
+			_, _, timestamp, err := q.parse([]string{"time", "value"}, []interface{}{tt.value, int64(1)}, now)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, timestamp)
+		})
+	}
+}
+
+func TestParseConversionError(t *testing.T) {
+	q := Query{Query: "SELECT 1", FieldColumnsInt: []string{"value"}}
+	require.NoError(t, q.init())
+
+	_, _, _, err := q.parse([]string{"value"}, []interface{}{"abc"}, time.Now())
+	require.Error(t, err)
+}
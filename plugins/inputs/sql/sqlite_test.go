@@ -0,0 +1,97 @@
+//go:build linux && (386 || amd64 || arm || arm64)
+// +build linux
+// +build 386 amd64 arm arm64
+
+package sql
+
+import (
+	dbsql "database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+)
+
+func TestSqlite(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "test.db")
+
+	db, err := dbsql.Open("sqlite", dsn)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		CREATE TABLE orders (region TEXT, shop TEXT, created TEXT, amount REAL, items INTEGER, paid INTEGER, comment TEXT);
+		INSERT INTO orders VALUES ('eu', 'berlin', '2021-03-01 10:00:00', 12.5, 3, 1, NULL);
+		INSERT INTO orders VALUES ('us', 'boston', '2021-03-01 11:30:00', 7, 1, 0, 'gift');
+	`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	plugin := &SQL{
+		Driver: "sqlite3",
+		Dsn:    dsn,
+		Queries: []Query{
+			{
+				Query:            "SELECT region, shop, created, amount, items, paid, comment FROM orders ORDER BY created",
+				Measurement:      "orders",
+				TimeColumn:       "created",
+				TimeFormat:       "2006-01-02 15:04:05",
+				TagColumns:       []string{"region", "shop"},
+				FieldColumnsBool: []string{"paid"},
+			},
+			{
+				Query:       "SELECT amount FROM orders WHERE amount > 100",
+				Measurement: "large_orders",
+			},
+			{
+				Query:       "SELECT region, COUNT(*) AS count FROM orders GROUP BY region ORDER BY region",
+				Measurement: "orders_per_region",
+				TagColumns:  []string{"region"},
+			},
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"orders",
+			map[string]string{"region": "eu", "shop": "berlin"},
+			map[string]interface{}{"amount": 12.5, "items": int64(3), "paid": true},
+			time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),
+		),
+		testutil.MustMetric(
+			"orders",
+			map[string]string{"region": "us", "shop": "boston"},
+			map[string]interface{}{"amount": 7.0, "items": int64(1), "paid": false, "comment": "gift"},
+			time.Date(2021, 3, 1, 11, 30, 0, 0, time.UTC),
+		),
+		testutil.MustMetric(
+			"orders_per_region",
+			map[string]string{"region": "eu"},
+			map[string]interface{}{"count": int64(1)},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric(
+			"orders_per_region",
+			map[string]string{"region": "us"},
+			map[string]interface{}{"count": int64(1)},
+			time.Unix(0, 0),
+		),
+	}
+	actual := acc.GetTelegrafMetrics()
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.SortMetrics(), testutil.IgnoreTime())
+	for _, m := range actual {
+		if m.Name() == "orders" {
+			require.Equal(t, 2021, m.Time().Year())
+		}
+	}
+}
@@ -0,0 +1 @@
+SELECT name, value FROM metrics
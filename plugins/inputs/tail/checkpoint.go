@@ -0,0 +1,120 @@
+//go:build !solaris
+// +build !solaris
+
+package tail
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"github.com/dimchansky/utfbom"
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/checkpoint"
+)
+
+// checkpointer stores the position of a line in the checkpoint store once
+// the metrics of the line and of all preceding lines of the file are
+// delivered.
+type checkpointer struct {
+	store *checkpoint.Store
+
+	sync.Mutex
+	files   map[string][]*pendingLine
+	pending map[telegraf.TrackingID]*pendingLine
+}
+
+type pendingLine struct {
+	filename  string
+	position  checkpoint.Position
+	delivered bool
+}
+
+func newCheckpointer(store *checkpoint.Store) *checkpointer {
+	return &checkpointer{
+		store:   store,
+		files:   make(map[string][]*pendingLine),
+		pending: make(map[telegraf.TrackingID]*pendingLine),
+	}
+}
+
+// add adds the metrics of a line ending at the given position to the
+// accumulator.  Lines without metrics are added as an empty group, which is
+// delivered immediately.
+func (c *checkpointer) add(acc telegraf.TrackingAccumulator, filename string, pos checkpoint.Position, metrics []telegraf.Metric) {
+	c.Lock()
+	defer c.Unlock()
+
+	// The lock is held while adding the metrics, so the line is known
+	// before its delivery is reported.
+	line := &pendingLine{filename: filename, position: pos}
+	c.files[filename] = append(c.files[filename], line)
+	c.pending[acc.AddTrackingMetricGroup(metrics)] = line
+}
+
+// delivered is called when the metrics of a line were delivered or
+// rejected.  Rejected metrics are dropped by the outputs and are not read
+// again.
+func (c *checkpointer) delivered(id telegraf.TrackingID) {
+	c.Lock()
+	defer c.Unlock()
+
+	line, ok := c.pending[id]
+	if !ok {
+		return
+	}
+	delete(c.pending, id)
+	line.delivered = true
+	c.commit(line.filename)
+}
+
+// commit stores the position of the last line of the file for which all
+// preceding lines are delivered.
+func (c *checkpointer) commit(filename string) {
+	lines := c.files[filename]
+	n := 0
+	for n < len(lines) && lines[n].delivered {
+		n++
+	}
+	if n == 0 {
+		return
+	}
+
+	c.store.Set(filename, lines[n-1].position)
+	if n == len(lines) {
+		delete(c.files, filename)
+		return
+	}
+	c.files[filename] = lines[n:]
+}
+
+// openPosition returns the position at which the tail library starts
+// reading the file after opening it.  The reader is the opened file.
+func openPosition(rd io.Reader) checkpoint.Position {
+	file, ok := rd.(*os.File)
+	if !ok {
+		return checkpoint.Position{}
+	}
+
+	var pos checkpoint.Position
+	if info, err := file.Stat(); err == nil {
+		pos.Identity = checkpoint.IdentityOf(info)
+	}
+	if offset, err := file.Seek(0, io.SeekCurrent); err == nil {
+		pos.Offset = offset
+	}
+	return pos
+}
+
+// bomLength returns the length of the byte order mark of the encoding.
+func bomLength(enc utfbom.Encoding) int64 {
+	switch enc {
+	case utfbom.UTF8:
+		return 3
+	case utfbom.UTF16BigEndian, utfbom.UTF16LittleEndian:
+		return 2
+	case utfbom.UTF32BigEndian, utfbom.UTF32LittleEndian:
+		return 4
+	}
+	return 0
+}
@@ -0,0 +1,238 @@
+package tail
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/agent"
+	"github.com/shanas-swi/telegraf-v1.16.3/internal"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/checkpoint"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/parsers"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type testMetricMaker struct{}
+
+func (tm *testMetricMaker) LogName() string {
+	return "tail"
+}
+
+func (tm *testMetricMaker) MakeMetric(metric telegraf.Metric) telegraf.Metric {
+	return metric
+}
+
+func (tm *testMetricMaker) Log() telegraf.Logger {
+	return testutil.Logger{}
+}
+
+// startCheckpointTail starts a tail plugin whose metrics are sent to the
+// returned channel, they have to be accepted to be delivered.
+func startCheckpointTail(t *testing.T, filename, checkpointFile string) (*Tail, chan telegraf.Metric) {
+	plugin := NewTail()
+	plugin.Log = testutil.Logger{}
+	plugin.Files = []string{filename}
+	plugin.FromBeginning = true
+	plugin.CheckpointFile = checkpointFile
+	plugin.SetParserFunc(parsers.NewInfluxParser)
+	require.NoError(t, plugin.Init())
+
+	dst := make(chan telegraf.Metric, 10)
+	require.NoError(t, plugin.Start(agent.NewAccumulator(&testMetricMaker{}, dst)))
+	return plugin, dst
+}
+
+func receive(t *testing.T, dst chan telegraf.Metric) telegraf.Metric {
+	select {
+	case m := <-dst:
+		return m
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timeout waiting for metric")
+	}
+	return nil
+}
+
+func requireCheckpoint(t *testing.T, plugin *Tail, filename string, offset int64) {
+	require.Eventually(t, func() bool {
+		pos, ok := plugin.checkpoints.store.Get(filename)
+		return ok && pos.Offset == offset
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tail")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "metrics.out")
+	checkpointFile := filepath.Join(dir, "checkpoint.json")
+
+	require.NoError(t, ioutil.WriteFile(filename, []byte("cpu value=1\ncpu value=2\n"), 0644))
+
+	plugin, dst := startCheckpointTail(t, filename, checkpointFile)
+	m := receive(t, dst)
+	require.Equal(t, 1.0, m.Fields()["value"])
+	m.Accept()
+	requireCheckpoint(t, plugin, filename, 12)
+
+	// The position of an undelivered line is not stored
+	m = receive(t, dst)
+	require.Equal(t, 2.0, m.Fields()["value"])
+	plugin.Stop()
+	m.Reject()
+
+	// Reading continues at the checkpoint instead of the beginning, lines
+	// not delivered before the restart are read again
+	appendFile(t, filename, "cpu value=3\n")
+	plugin, dst = startCheckpointTail(t, filename, checkpointFile)
+	for _, value := range []float64{2, 3} {
+		m = receive(t, dst)
+		require.Equal(t, value, m.Fields()["value"])
+		m.Accept()
+	}
+	requireCheckpoint(t, plugin, filename, 36)
+	plugin.Stop()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("Rotated files are only detected by truncation on windows")
+	}
+
+	// A file replaced while Telegraf was stopped is read from the beginning
+	require.NoError(t, os.Rename(filename, filename+".1"))
+	require.NoError(t, ioutil.WriteFile(filename, []byte("cpu value=4\n"), 0644))
+	plugin, dst = startCheckpointTail(t, filename, checkpointFile)
+	defer plugin.Stop()
+	m = receive(t, dst)
+	require.Equal(t, 4.0, m.Fields()["value"])
+	m.Accept()
+	requireCheckpoint(t, plugin, filename, 12)
+
+	info, err := os.Stat(filename)
+	require.NoError(t, err)
+	pos, _ := plugin.checkpoints.store.Get(filename)
+	require.Equal(t, checkpoint.IdentityOf(info), pos.Identity)
+}
+
+func TestCheckpointRotation(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Files can not be renamed while open on windows")
+	}
+
+	dir, err := ioutil.TempDir("", "tail")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "metrics.out")
+	require.NoError(t, ioutil.WriteFile(filename, []byte("cpu value=1\n"), 0644))
+
+	plugin, dst := startCheckpointTail(t, filename, filepath.Join(dir, "checkpoint.json"))
+	defer plugin.Stop()
+	m := receive(t, dst)
+	m.Accept()
+	requireCheckpoint(t, plugin, filename, 12)
+
+	// The position of the lines read after the file was reopened refers to
+	// the new file
+	require.NoError(t, os.Rename(filename, filename+".1"))
+	require.NoError(t, ioutil.WriteFile(filename, []byte("cpu value=2\n"), 0644))
+	m = receive(t, dst)
+	require.Equal(t, 2.0, m.Fields()["value"])
+	m.Accept()
+
+	info, err := os.Stat(filename)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		pos, _ := plugin.checkpoints.store.Get(filename)
+		return pos == checkpoint.Position{Identity: checkpoint.IdentityOf(info), Offset: 12}
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestCheckpointMultiline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tail")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "metrics.out")
+	// The first event is complete when the second one starts, the position
+	// of the second event is not stored until it is complete
+	require.NoError(t, ioutil.WriteFile(filename, []byte("cpu\n value=1\nmem\n"), 0644))
+
+	plugin := NewTail()
+	plugin.Log = testutil.Logger{}
+	plugin.Files = []string{filename}
+	plugin.FromBeginning = true
+	plugin.CheckpointFile = filepath.Join(dir, "checkpoint.json")
+	plugin.MultilineConfig = MultilineConfig{
+		Pattern:        `^\s`,
+		MatchWhichLine: Previous,
+		Timeout:        &internal.Duration{Duration: time.Minute},
+	}
+	plugin.SetParserFunc(parsers.NewInfluxParser)
+	require.NoError(t, plugin.Init())
+
+	dst := make(chan telegraf.Metric, 10)
+	require.NoError(t, plugin.Start(agent.NewAccumulator(&testMetricMaker{}, dst)))
+	defer plugin.Stop()
+
+	m := receive(t, dst)
+	require.Equal(t, 1.0, m.Fields()["value"])
+	m.Accept()
+	requireCheckpoint(t, plugin, filename, 13)
+}
+
+func TestCheckpointOrder(t *testing.T) {
+	store, err := checkpoint.Open(filepath.Join(os.TempDir(), "does-not-exist.json"))
+	require.NoError(t, err)
+	c := newCheckpointer(store)
+
+	var acc testutil.Accumulator
+	m := testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"value": 1.0}, time.Unix(0, 0))
+	pos := func(offset int64) checkpoint.Position {
+		return checkpoint.Position{Offset: offset}
+	}
+
+	c.add(&acc, "a.log", pos(10), []telegraf.Metric{m})
+	c.add(&acc, "a.log", pos(20), []telegraf.Metric{m})
+	c.add(&acc, "b.log", pos(5), []telegraf.Metric{m})
+	c.add(&acc, "a.log", pos(30), []telegraf.Metric{m})
+
+	require.Len(t, c.pending, 4)
+	byOffset := func(filename string, offset int64) telegraf.TrackingID {
+		for id, line := range c.pending {
+			if line.filename == filename && line.position.Offset == offset {
+				return id
+			}
+		}
+		require.FailNow(t, "line not pending")
+		return 0
+	}
+
+	// A line is only committed when all preceding lines are delivered
+	c.delivered(byOffset("a.log", 20))
+	_, ok := store.Get("a.log")
+	require.False(t, ok)
+
+	c.delivered(byOffset("b.log", 5))
+	actual, _ := store.Get("b.log")
+	require.Equal(t, pos(5), actual)
+
+	c.delivered(byOffset("a.log", 10))
+	actual, _ = store.Get("a.log")
+	require.Equal(t, pos(20), actual)
+
+	c.delivered(byOffset("a.log", 30))
+	actual, _ = store.Get("a.log")
+	require.Equal(t, pos(30), actual)
+	require.Empty(t, c.files)
+	require.Empty(t, c.pending)
+}
+
+func appendFile(t *testing.T, filename, content string) {
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+}
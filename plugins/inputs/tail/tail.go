@@ -16,6 +16,7 @@ import (
 	"github.com/influxdata/tail"
 	"github.com/shanas-swi/telegraf-v1.16.3"
 	"github.com/shanas-swi/telegraf-v1.16.3/internal/globpath"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/checkpoint"
 	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/encoding"
 	"github.com/shanas-swi/telegraf-v1.16.3/plugins/inputs"
 	"github.com/shanas-swi/telegraf-v1.16.3/plugins/parsers"
@@ -42,6 +43,7 @@ type Tail struct {
 	WatchMethod         string   `toml:"watch_method"`
 	MaxUndeliveredLines int      `toml:"max_undelivered_lines"`
 	CharacterEncoding   string   `toml:"character_encoding"`
+	CheckpointFile      string   `toml:"checkpoint_file"`
 
 	Log        telegraf.Logger `toml:"-"`
 	tailers    map[string]*tail.Tail
@@ -58,6 +60,9 @@ type Tail struct {
 	cancel  context.CancelFunc
 	sem     semaphore
 	decoder *encoding.Decoder
+
+	checkpoints *checkpointer
+	stopping    chan struct{}
 }
 
 func NewTail() *Tail {
@@ -110,6 +115,13 @@ const sampleConfig = `
   ##       character_encoding = ""
   # character_encoding = ""
 
+  ## File storing the read position of each file, used to continue reading
+  ## after a restart.  The position of a line is stored once its metrics were
+  ## written by the outputs.  Files which were rotated or truncated since are
+  ## read from the beginning.  Requires an empty character_encoding and
+  ## cannot be used with pipes.
+  # checkpoint_file = ""
+
   ## Data format to consume.
   ## Each data format has its own unique set of configuration options, read
   ## more about them here:
@@ -154,17 +166,44 @@ func (t *Tail) Init() error {
 
 	var err error
 	t.decoder, err = encoding.NewDecoder(t.CharacterEncoding)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if t.CheckpointFile != "" {
+		// The positions are counted in bytes of the file content, so the
+		// content must not be decoded.
+		if t.CharacterEncoding != "" {
+			return errors.New("checkpoint_file cannot be used with character_encoding")
+		}
+		if t.Pipe {
+			return errors.New("checkpoint_file cannot be used with pipe")
+		}
+
+		store, err := checkpoint.Open(t.CheckpointFile)
+		if err != nil {
+			return err
+		}
+		t.checkpoints = newCheckpointer(store)
+	}
+	return nil
 }
 
 func (t *Tail) Gather(acc telegraf.Accumulator) error {
-	return t.tailNewFiles(true)
+	if err := t.tailNewFiles(true); err != nil {
+		return err
+	}
+	if t.checkpoints != nil {
+		return t.checkpoints.store.Save()
+	}
+	return nil
 }
 
 func (t *Tail) Start(acc telegraf.Accumulator) error {
 	t.acc = acc.WithTracking(t.MaxUndeliveredLines)
 
 	t.ctx, t.cancel = context.WithCancel(context.Background())
+	t.stopping = make(chan struct{})
 
 	t.wg.Add(1)
 	go func() {
@@ -173,8 +212,11 @@ func (t *Tail) Start(acc telegraf.Accumulator) error {
 			select {
 			case <-t.ctx.Done():
 				return
-			case <-t.acc.Delivered():
+			case info := <-t.acc.Delivered():
 				<-t.sem
+				if t.checkpoints != nil {
+					t.checkpoints.delivered(info.ID())
+				}
 			}
 		}
 	}()
@@ -219,7 +261,16 @@ func (t *Tail) tailNewFiles(fromBeginning bool) error {
 			}
 
 			var seek *tail.SeekInfo
-			if !t.Pipe && !fromBeginning {
+			if t.checkpoints != nil {
+				if offset, ok := t.checkpoints.store.Offset(file); ok {
+					t.Log.Debugf("Using checkpoint offset %d for %q", offset, file)
+					seek = &tail.SeekInfo{
+						Whence: 0,
+						Offset: offset,
+					}
+				}
+			}
+			if seek == nil && !t.Pipe && !fromBeginning {
 				if offset, ok := t.offsets[file]; ok {
 					t.Log.Debugf("Using offset %d for %q", offset, file)
 					seek = &tail.SeekInfo{
@@ -234,6 +285,16 @@ func (t *Tail) tailNewFiles(fromBeginning bool) error {
 				}
 			}
 
+			// The position at which the file is read is passed to the
+			// receiver each time the file is opened, including reopening
+			// it after a rotation.  The lines channel of the tailer is
+			// unbuffered, so the receiver gets the position after all lines
+			// read before.
+			var opened chan checkpoint.Position
+			if t.checkpoints != nil {
+				opened = make(chan checkpoint.Position)
+			}
+
 			tailer, err := tail.TailFile(file,
 				tail.Config{
 					ReOpen:    true,
@@ -244,7 +305,18 @@ func (t *Tail) tailNewFiles(fromBeginning bool) error {
 					Pipe:      t.Pipe,
 					Logger:    tail.DiscardingLogger,
 					OpenReaderFunc: func(rd io.Reader) io.Reader {
-						r, _ := utfbom.Skip(t.decoder.Reader(rd))
+						var pos checkpoint.Position
+						if opened != nil {
+							pos = openPosition(rd)
+						}
+						r, enc := utfbom.Skip(t.decoder.Reader(rd))
+						if opened != nil {
+							pos.Offset += bomLength(enc)
+							select {
+							case opened <- pos:
+							case <-t.stopping:
+							}
+						}
 						return r
 					},
 				})
@@ -267,7 +339,7 @@ func (t *Tail) tailNewFiles(fromBeginning bool) error {
 
 			go func() {
 				defer t.wg.Done()
-				t.receiver(parser, tailer)
+				t.receiver(parser, tailer, opened)
 
 				t.Log.Debugf("Tail removed for %q", tailer.Filename)
 
@@ -309,12 +381,16 @@ func parseLine(parser parsers.Parser, line string, firstLine bool) ([]telegraf.M
 
 // Receiver is launched as a goroutine to continuously watch a tailed logfile
 // for changes, parse any incoming msgs, and add to the accumulator.
-func (t *Tail) receiver(parser parsers.Parser, tailer *tail.Tail) {
+func (t *Tail) receiver(parser parsers.Parser, tailer *tail.Tail, opened <-chan checkpoint.Position) {
 	var firstLine = true
 
 	// holds the individual lines of multi-line log entries.
 	var buffer bytes.Buffer
 
+	// position after the last line read and of the first line in the
+	// multi-line buffer
+	var pos, bufferPos checkpoint.Position
+
 	var timer *time.Timer
 	var timeout <-chan time.Time
 
@@ -339,6 +415,8 @@ func (t *Tail) receiver(parser parsers.Parser, tailer *tail.Tail) {
 		select {
 		case <-t.ctx.Done():
 			channelOpen = false
+		case pos = <-opened:
+			continue
 		case line, tailerOpen = <-tailer.Lines:
 			if !tailerOpen {
 				channelOpen = false
@@ -349,13 +427,25 @@ func (t *Tail) receiver(parser parsers.Parser, tailer *tail.Tail) {
 		var text string
 
 		if line != nil {
+			lineStart := pos
+			if line.Err == nil {
+				pos.Offset += int64(len(line.Text)) + 1
+			}
+
 			// Fix up files with Windows line endings.
 			text = strings.TrimRight(line.Text, "\r")
 
 			if t.multiline.IsEnabled() {
+				if buffer.Len() == 0 {
+					bufferPos = lineStart
+				}
 				if text = t.multiline.ProcessLine(text, &buffer); text == "" {
 					continue
 				}
+				if buffer.Len() > 0 {
+					// The buffer was replaced by the current line
+					bufferPos = lineStart
+				}
 			}
 		}
 		if line == nil || !channelOpen || !tailerOpen {
@@ -373,13 +463,25 @@ func (t *Tail) receiver(parser parsers.Parser, tailer *tail.Tail) {
 			continue
 		}
 
+		// Position up to which the file is processed once the metrics are
+		// delivered
+		commitPos := pos
+		if buffer.Len() > 0 {
+			commitPos = bufferPos
+		}
+
 		metrics, err := parseLine(parser, text, firstLine)
 		if err != nil {
 			t.Log.Errorf("Malformed log line in %q: [%q]: %s",
 				tailer.Filename, text, err.Error())
-			continue
+			if t.checkpoints == nil {
+				continue
+			}
+			// Commit the position of the line anyway
+			metrics = nil
+		} else {
+			firstLine = false
 		}
-		firstLine = false
 
 		for _, metric := range metrics {
 			metric.AddTag("path", tailer.Filename)
@@ -388,7 +490,7 @@ func (t *Tail) receiver(parser parsers.Parser, tailer *tail.Tail) {
 		// try writing out metric first without blocking
 		select {
 		case t.sem <- empty{}:
-			t.acc.AddTrackingMetricGroup(metrics)
+			t.addMetrics(tailer.Filename, commitPos, metrics)
 			if t.ctx.Err() != nil {
 				return // exit!
 			}
@@ -402,12 +504,25 @@ func (t *Tail) receiver(parser parsers.Parser, tailer *tail.Tail) {
 		case <-t.ctx.Done():
 			return
 		case t.sem <- empty{}:
-			t.acc.AddTrackingMetricGroup(metrics)
+			t.addMetrics(tailer.Filename, commitPos, metrics)
 		}
 	}
 }
 
+func (t *Tail) addMetrics(filename string, pos checkpoint.Position, metrics []telegraf.Metric) {
+	if t.checkpoints != nil {
+		t.checkpoints.add(t.acc, filename, pos, metrics)
+		return
+	}
+	t.acc.AddTrackingMetricGroup(metrics)
+}
+
 func (t *Tail) Stop() {
+	select {
+	case <-t.stopping:
+	default:
+		close(t.stopping)
+	}
 	for _, tailer := range t.tailers {
 		if !t.Pipe && !t.FromBeginning {
 			// store offset for resume
@@ -427,6 +542,12 @@ func (t *Tail) Stop() {
 	t.cancel()
 	t.wg.Wait()
 
+	if t.checkpoints != nil {
+		if err := t.checkpoints.store.Save(); err != nil {
+			t.Log.Errorf("Saving checkpoints: %s", err.Error())
+		}
+	}
+
 	// persist offsets
 	offsetsMutex.Lock()
 	for k, v := range t.offsets {
@@ -29,11 +29,14 @@ import (
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs/nats"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs/newrelic"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs/nsq"
+	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs/opentelemetry"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs/opentsdb"
+	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs/parquet"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs/prometheus_client"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs/riemann"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs/riemann_legacy"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs/socket_writer"
+	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs/sql"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs/stackdriver"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs/sumologic"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs/syslog"
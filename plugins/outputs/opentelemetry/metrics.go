@@ -0,0 +1,300 @@
+package opentelemetry
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/internal"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/otlp"
+)
+
+const scopeName = "telegraf"
+
+// converter builds OTLP metric data from telegraf metrics.  The value type of
+// each metric decides the kind of data point:
+//   - counters become monotonic cumulative sums
+//   - gauges and untyped metrics become gauges
+//   - histograms and summaries use the field layout of the prometheus input,
+//     with "count" and "sum" fields and a field per bucket or quantile.
+type converter struct {
+	resourceTags map[string]bool
+	log          telegraf.Logger
+}
+
+func newConverter(resourceTags []string, log telegraf.Logger) *converter {
+	c := &converter{
+		resourceTags: make(map[string]bool, len(resourceTags)),
+		log:          log,
+	}
+	for _, tag := range resourceTags {
+		c.resourceTags[tag] = true
+	}
+	return c
+}
+
+// resourceBuilder collects the metrics of a single resource.
+type resourceBuilder struct {
+	resource *otlp.ResourceMetrics
+	scope    *otlp.ScopeMetrics
+	metrics  map[string]*otlp.Metric
+}
+
+func (c *converter) convert(metrics []telegraf.Metric) []*otlp.ResourceMetrics {
+	var order []string
+	resources := make(map[string]*resourceBuilder)
+
+	for _, m := range metrics {
+		resourceAttrs, pointAttrs := c.splitTags(m)
+
+		key := attributesKey(resourceAttrs)
+		rb, ok := resources[key]
+		if !ok {
+			rb = &resourceBuilder{
+				scope: &otlp.ScopeMetrics{
+					Scope: &otlp.InstrumentationScope{
+						Name:    scopeName,
+						Version: internal.Version(),
+					},
+				},
+				metrics: make(map[string]*otlp.Metric),
+			}
+			rb.resource = &otlp.ResourceMetrics{
+				Resource:     &otlp.Resource{Attributes: resourceAttrs},
+				ScopeMetrics: []*otlp.ScopeMetrics{rb.scope},
+			}
+			resources[key] = rb
+			order = append(order, key)
+		}
+
+		switch m.Type() {
+		case telegraf.Counter:
+			c.addNumbers(rb, m, pointAttrs, true)
+		case telegraf.Histogram:
+			c.addHistogram(rb, m, pointAttrs)
+		case telegraf.Summary:
+			c.addSummary(rb, m, pointAttrs)
+		default:
+			c.addNumbers(rb, m, pointAttrs, false)
+		}
+	}
+
+	result := make([]*otlp.ResourceMetrics, 0, len(order))
+	for _, key := range order {
+		if rb := resources[key]; len(rb.scope.Metrics) > 0 {
+			result = append(result, rb.resource)
+		}
+	}
+	return result
+}
+
+func (c *converter) splitTags(m telegraf.Metric) ([]*otlp.KeyValue, []*otlp.KeyValue) {
+	var resourceAttrs, pointAttrs []*otlp.KeyValue
+	for _, tag := range m.TagList() {
+		kv := &otlp.KeyValue{
+			Key:   tag.Key,
+			Value: &otlp.AnyValue{Value: &otlp.AnyValue_StringValue{StringValue: tag.Value}},
+		}
+		if c.resourceTags[tag.Key] {
+			resourceAttrs = append(resourceAttrs, kv)
+		} else {
+			pointAttrs = append(pointAttrs, kv)
+		}
+	}
+	return resourceAttrs, pointAttrs
+}
+
+// metric returns the OTLP metric with the given name and kind in the
+// resource, creating it if needed.
+func (rb *resourceBuilder) metric(name string, kind string, create func() *otlp.Metric) *otlp.Metric {
+	key := kind + "\x00" + name
+	om, ok := rb.metrics[key]
+	if !ok {
+		om = create()
+		om.Name = name
+		rb.metrics[key] = om
+		rb.scope.Metrics = append(rb.scope.Metrics, om)
+	}
+	return om
+}
+
+func (c *converter) addNumbers(rb *resourceBuilder, m telegraf.Metric, attrs []*otlp.KeyValue, monotonic bool) {
+	for _, field := range m.FieldList() {
+		dp := &otlp.NumberDataPoint{
+			Attributes:   attrs,
+			TimeUnixNano: uint64(m.Time().UnixNano()),
+		}
+		switch v := field.Value.(type) {
+		case int64:
+			dp.Value = &otlp.NumberDataPoint_AsInt{AsInt: v}
+		case uint64:
+			if v <= math.MaxInt64 {
+				dp.Value = &otlp.NumberDataPoint_AsInt{AsInt: int64(v)}
+			} else {
+				dp.Value = &otlp.NumberDataPoint_AsDouble{AsDouble: float64(v)}
+			}
+		case float64:
+			dp.Value = &otlp.NumberDataPoint_AsDouble{AsDouble: v}
+		case bool:
+			var i int64
+			if v {
+				i = 1
+			}
+			dp.Value = &otlp.NumberDataPoint_AsInt{AsInt: i}
+		default:
+			c.log.Debugf("Dropping field %q of %q with unsupported type %T", field.Key, m.Name(), field.Value)
+			continue
+		}
+
+		name := metricName(m.Name(), field.Key)
+		if monotonic {
+			om := rb.metric(name, "sum", func() *otlp.Metric {
+				return &otlp.Metric{Data: &otlp.Metric_Sum{Sum: &otlp.Sum{
+					IsMonotonic:            true,
+					AggregationTemporality: otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+				}}}
+			})
+			sum := om.GetSum()
+			sum.DataPoints = append(sum.DataPoints, dp)
+		} else {
+			om := rb.metric(name, "gauge", func() *otlp.Metric {
+				return &otlp.Metric{Data: &otlp.Metric_Gauge{Gauge: &otlp.Gauge{}}}
+			})
+			gauge := om.GetGauge()
+			gauge.DataPoints = append(gauge.DataPoints, dp)
+		}
+	}
+}
+
+type bucket struct {
+	bound float64
+	count float64
+}
+
+func (c *converter) addHistogram(rb *resourceBuilder, m telegraf.Metric, attrs []*otlp.KeyValue) {
+	dp := &otlp.HistogramDataPoint{
+		Attributes:   attrs,
+		TimeUnixNano: uint64(m.Time().UnixNano()),
+	}
+
+	var buckets []bucket
+	for _, field := range m.FieldList() {
+		value, ok := toFloat(field.Value)
+		if !ok {
+			continue
+		}
+		switch field.Key {
+		case "count":
+			dp.Count = uint64(value)
+		case "sum":
+			dp.Sum = &value
+		case "min":
+			dp.Min = &value
+		case "max":
+			dp.Max = &value
+		default:
+			bound, err := strconv.ParseFloat(field.Key, 64)
+			if err != nil {
+				continue
+			}
+			buckets = append(buckets, bucket{bound: bound, count: value})
+		}
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].bound < buckets[j].bound })
+
+	// Buckets are cumulative in telegraf but not in OTLP; the +Inf bucket is
+	// implicit in OTLP and derived from the total count.
+	var previous float64
+	for _, b := range buckets {
+		if math.IsInf(b.bound, 1) {
+			break
+		}
+		dp.ExplicitBounds = append(dp.ExplicitBounds, b.bound)
+		dp.BucketCounts = append(dp.BucketCounts, uint64(math.Max(b.count-previous, 0)))
+		previous = b.count
+	}
+	dp.BucketCounts = append(dp.BucketCounts, uint64(math.Max(float64(dp.Count)-previous, 0)))
+
+	om := rb.metric(m.Name(), "histogram", func() *otlp.Metric {
+		return &otlp.Metric{Data: &otlp.Metric_Histogram{Histogram: &otlp.Histogram{
+			AggregationTemporality: otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+		}}}
+	})
+	histogram := om.GetHistogram()
+	histogram.DataPoints = append(histogram.DataPoints, dp)
+}
+
+func (c *converter) addSummary(rb *resourceBuilder, m telegraf.Metric, attrs []*otlp.KeyValue) {
+	dp := &otlp.SummaryDataPoint{
+		Attributes:   attrs,
+		TimeUnixNano: uint64(m.Time().UnixNano()),
+	}
+
+	for _, field := range m.FieldList() {
+		value, ok := toFloat(field.Value)
+		if !ok {
+			continue
+		}
+		switch field.Key {
+		case "count":
+			dp.Count = uint64(value)
+		case "sum":
+			dp.Sum = value
+		default:
+			quantile, err := strconv.ParseFloat(field.Key, 64)
+			if err != nil || quantile < 0 || quantile > 1 {
+				continue
+			}
+			dp.QuantileValues = append(dp.QuantileValues, &otlp.SummaryDataPoint_ValueAtQuantile{
+				Quantile: quantile,
+				Value:    value,
+			})
+		}
+	}
+	sort.Slice(dp.QuantileValues, func(i, j int) bool {
+		return dp.QuantileValues[i].Quantile < dp.QuantileValues[j].Quantile
+	})
+
+	om := rb.metric(m.Name(), "summary", func() *otlp.Metric {
+		return &otlp.Metric{Data: &otlp.Metric_Summary{Summary: &otlp.Summary{}}}
+	})
+	summary := om.GetSummary()
+	summary.DataPoints = append(summary.DataPoints, dp)
+}
+
+// metricName joins the measurement and field name; the generic field names
+// used by the prometheus and opentelemetry inputs map to the measurement.
+func metricName(measurement, field string) string {
+	switch field {
+	case "value", "gauge", "counter":
+		return measurement
+	default:
+		return measurement + "_" + field
+	}
+}
+
+func attributesKey(attrs []*otlp.KeyValue) string {
+	var b strings.Builder
+	for _, kv := range attrs {
+		b.WriteString(kv.GetKey())
+		b.WriteByte(0)
+		b.WriteString(kv.GetValue().GetStringValue())
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch value := v.(type) {
+	case int64:
+		return float64(value), true
+	case uint64:
+		return float64(value), true
+	case float64:
+		return value, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,154 @@
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/internal"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/otlp"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/tls"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	defaultServiceAddress = "localhost:4317"
+	defaultTimeout        = 5 * time.Second
+)
+
+var sampleConfig = `
+  ## Address of the OpenTelemetry collector accepting OTLP over gRPC.
+  service_address = "localhost:4317"
+
+  ## Timeout for a single export request.
+  # timeout = "5s"
+
+  ## Compression of the export requests, one of "none" or "gzip".
+  # compression = "none"
+
+  ## Tags to promote to resource attributes; all other tags are sent as data
+  ## point attributes.  Metrics are grouped by the values of these tags.
+  # resource_tags = ["host"]
+
+  ## Optional TLS Config
+  # tls_ca = "/etc/telegraf/ca.pem"
+  # tls_cert = "/etc/telegraf/cert.pem"
+  # tls_key = "/etc/telegraf/key.pem"
+  ## Use TLS but skip chain & host verification
+  # insecure_skip_verify = false
+
+  ## Additional gRPC request metadata, for example for authentication.
+  # [outputs.opentelemetry.headers]
+  #   key1 = "value1"
+`
+
+type OpenTelemetry struct {
+	ServiceAddress string            `toml:"service_address"`
+	Timeout        internal.Duration `toml:"timeout"`
+	Compression    string            `toml:"compression"`
+	ResourceTags   []string          `toml:"resource_tags"`
+	Headers        map[string]string `toml:"headers"`
+	Log            telegraf.Logger   `toml:"-"`
+	tls.ClientConfig
+
+	conn      *grpc.ClientConn
+	client    otlp.MetricsServiceClient
+	callOpts  []grpc.CallOption
+	converter *converter
+}
+
+func (o *OpenTelemetry) SampleConfig() string {
+	return sampleConfig
+}
+
+func (o *OpenTelemetry) Description() string {
+	return "Send metrics to an OpenTelemetry collector using OTLP over gRPC"
+}
+
+func (o *OpenTelemetry) Connect() error {
+	if o.ServiceAddress == "" {
+		o.ServiceAddress = defaultServiceAddress
+	}
+	if o.Timeout.Duration == 0 {
+		o.Timeout.Duration = defaultTimeout
+	}
+
+	switch o.Compression {
+	case "", "none":
+	case "gzip":
+		o.callOpts = append(o.callOpts, grpc.UseCompressor(gzip.Name))
+	default:
+		return fmt.Errorf("unsupported compression %q", o.Compression)
+	}
+
+	tlsCfg, err := o.ClientConfig.TLSConfig()
+	if err != nil {
+		return err
+	}
+
+	var opts []grpc.DialOption
+	if tlsCfg != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	o.conn, err = grpc.Dial(o.ServiceAddress, opts...)
+	if err != nil {
+		return err
+	}
+	o.client = otlp.NewMetricsServiceClient(o.conn)
+	o.converter = newConverter(o.ResourceTags, o.Log)
+
+	return nil
+}
+
+func (o *OpenTelemetry) Close() error {
+	if o.conn == nil {
+		return nil
+	}
+	err := o.conn.Close()
+	o.conn = nil
+	return err
+}
+
+func (o *OpenTelemetry) Write(metrics []telegraf.Metric) error {
+	req := &otlp.ExportMetricsServiceRequest{
+		ResourceMetrics: o.converter.convert(metrics),
+	}
+	if len(req.ResourceMetrics) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.Timeout.Duration)
+	defer cancel()
+
+	if len(o.Headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(o.Headers))
+	}
+
+	resp, err := o.client.Export(ctx, req, o.callOpts...)
+	if err != nil {
+		return err
+	}
+
+	if partial := resp.GetPartialSuccess(); partial != nil && partial.GetRejectedDataPoints() > 0 {
+		o.Log.Warnf("Collector rejected %d data points: %s",
+			partial.GetRejectedDataPoints(), partial.GetErrorMessage())
+	}
+	return nil
+}
+
+func init() {
+	outputs.Add("opentelemetry", func() telegraf.Output {
+		return &OpenTelemetry{
+			ServiceAddress: defaultServiceAddress,
+			Timeout:        internal.Duration{Duration: defaultTimeout},
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package opentelemetry
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/common/otlp"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type mockCollector struct {
+	requests []*otlp.ExportMetricsServiceRequest
+	metadata []metadata.MD
+}
+
+func (c *mockCollector) Export(ctx context.Context, req *otlp.ExportMetricsServiceRequest) (*otlp.ExportMetricsServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	c.requests = append(c.requests, req)
+	c.metadata = append(c.metadata, md)
+	return &otlp.ExportMetricsServiceResponse{}, nil
+}
+
+func stringAttr(key, value string) *otlp.KeyValue {
+	return &otlp.KeyValue{
+		Key:   key,
+		Value: &otlp.AnyValue{Value: &otlp.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func TestConvert(t *testing.T) {
+	now := time.Unix(1, 0)
+	metrics := []telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "a", "cpu": "cpu0"},
+			map[string]interface{}{"usage_idle": 99.5, "name": "skipped"},
+			now, telegraf.Gauge),
+		testutil.MustMetric("requests",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"counter": int64(42)},
+			now, telegraf.Counter),
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "b", "cpu": "cpu0"},
+			map[string]interface{}{"usage_idle": 50.0},
+			now, telegraf.Untyped),
+		testutil.MustMetric("latency",
+			map[string]string{"host": "a"},
+			map[string]interface{}{
+				"count": 6.0,
+				"sum":   4.2,
+				"0.5":   3.0,
+				"1":     5.0,
+				"+Inf":  6.0,
+			},
+			now, telegraf.Histogram),
+		testutil.MustMetric("rpc",
+			map[string]string{"host": "a"},
+			map[string]interface{}{
+				"count": 10.0,
+				"sum":   5.0,
+				"0.99":  1.2,
+				"0.5":   0.4,
+			},
+			now, telegraf.Summary),
+	}
+
+	resources := newConverter([]string{"host"}, testutil.Logger{}).convert(metrics)
+	require.Len(t, resources, 2)
+
+	require.Equal(t, []*otlp.KeyValue{stringAttr("host", "a")}, resources[0].GetResource().GetAttributes())
+	scope := resources[0].GetScopeMetrics()[0]
+	require.Equal(t, "telegraf", scope.GetScope().GetName())
+	require.Len(t, scope.GetMetrics(), 4)
+
+	gauge := scope.GetMetrics()[0]
+	require.Equal(t, "cpu_usage_idle", gauge.GetName())
+	require.Len(t, gauge.GetGauge().GetDataPoints(), 1)
+	dp := gauge.GetGauge().GetDataPoints()[0]
+	require.Equal(t, 99.5, dp.GetAsDouble())
+	require.Equal(t, uint64(1e9), dp.GetTimeUnixNano())
+	require.Equal(t, []*otlp.KeyValue{stringAttr("cpu", "cpu0")}, dp.GetAttributes())
+
+	sum := scope.GetMetrics()[1]
+	require.Equal(t, "requests", sum.GetName())
+	require.True(t, sum.GetSum().GetIsMonotonic())
+	require.Equal(t, otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, sum.GetSum().GetAggregationTemporality())
+	require.Equal(t, int64(42), sum.GetSum().GetDataPoints()[0].GetAsInt())
+
+	histogram := scope.GetMetrics()[2].GetHistogram().GetDataPoints()[0]
+	require.Equal(t, uint64(6), histogram.GetCount())
+	require.Equal(t, 4.2, histogram.GetSum())
+	require.Equal(t, []float64{0.5, 1}, histogram.GetExplicitBounds())
+	require.Equal(t, []uint64{3, 2, 1}, histogram.GetBucketCounts())
+
+	summary := scope.GetMetrics()[3].GetSummary().GetDataPoints()[0]
+	require.Equal(t, uint64(10), summary.GetCount())
+	require.Equal(t, 5.0, summary.GetSum())
+	require.Len(t, summary.GetQuantileValues(), 2)
+	require.Equal(t, 0.5, summary.GetQuantileValues()[0].GetQuantile())
+	require.Equal(t, 1.2, summary.GetQuantileValues()[1].GetValue())
+
+	require.Equal(t, []*otlp.KeyValue{stringAttr("host", "b")}, resources[1].GetResource().GetAttributes())
+	require.Equal(t, 50.0, resources[1].GetScopeMetrics()[0].GetMetrics()[0].GetGauge().GetDataPoints()[0].GetAsDouble())
+}
+
+func TestWrite(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	collector := &mockCollector{}
+	server := grpc.NewServer()
+	otlp.RegisterMetricsServiceServer(server, collector)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	plugin := &OpenTelemetry{
+		ServiceAddress: listener.Addr().String(),
+		Compression:    "gzip",
+		Headers:        map[string]string{"authorization": "Bearer token"},
+		Log:            testutil.Logger{},
+	}
+	require.NoError(t, plugin.Connect())
+	defer plugin.Close()
+
+	err = plugin.Write([]telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"value": 42.0},
+			time.Unix(1, 0)),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, collector.requests, 1)
+	require.Equal(t, []string{"Bearer token"}, collector.metadata[0].Get("authorization"))
+	metrics := collector.requests[0].GetResourceMetrics()[0].GetScopeMetrics()[0].GetMetrics()
+	require.Equal(t, "cpu", metrics[0].GetName())
+	require.Equal(t, 42.0, metrics[0].GetGauge().GetDataPoints()[0].GetAsDouble())
+}
+
+func TestUnsupportedCompression(t *testing.T) {
+	plugin := &OpenTelemetry{Compression: "zstd"}
+	require.Error(t, plugin.Connect())
+}
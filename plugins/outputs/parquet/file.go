@@ -0,0 +1,150 @@
+package parquet
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/internal/rotate"
+	"github.com/xitongsys/parquet-go/marshal"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const (
+	// activeFilename is the name of the file currently written in a
+	// partition; it is not a valid parquet file until it is rotated.
+	activeFilename = ".metrics.parquet.tmp"
+	// rotatedFilenameTemplate follows the naming of internal/rotate.
+	rotatedFilenameTemplate = "metrics.%s-%s.parquet"
+)
+
+// localFile implements the parquet source interface for local files.
+type localFile struct {
+	*os.File
+}
+
+// Open opens the named file; the parquet reader opens additional handles of
+// the same file by passing an empty name.
+func (f *localFile) Open(name string) (source.ParquetFile, error) {
+	if name == "" {
+		name = f.Name()
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &localFile{file}, nil
+}
+
+func (f *localFile) Create(name string) (source.ParquetFile, error) {
+	file, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, rotate.FilePerm)
+	if err != nil {
+		return nil, err
+	}
+	return &localFile{file}, nil
+}
+
+// partitionFile writes the rows of a measurement partition to a parquet
+// file.  The file is rotated when the rotation interval expired, when it
+// exceeds the maximum size or when the schema changes.
+type partitionFile struct {
+	dir        string
+	schema     *tableSchema
+	file       source.ParquetFile
+	writer     *writer.ParquetWriter
+	expireTime time.Time
+}
+
+func openPartitionFile(dir string, schema *tableSchema, codec parquet.CompressionCodec, interval time.Duration) (*partitionFile, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	// A leftover active file is the result of an unclean shutdown and lacks
+	// the parquet footer, so it is overwritten.
+	file, err := (&localFile{}).Create(filepath.Join(dir, activeFilename))
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := writer.NewParquetWriter(file, schema.elements(), 1)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	w.MarshalFunc = marshal.MarshalCSV
+	w.CompressionType = codec
+
+	pf := &partitionFile{
+		dir:    dir,
+		schema: schema,
+		file:   file,
+		writer: w,
+	}
+	if interval > 0 {
+		pf.expireTime = time.Now().Add(interval)
+	}
+	return pf, nil
+}
+
+func (pf *partitionFile) write(m telegraf.Metric) error {
+	return pf.writer.Write(pf.schema.row(m))
+}
+
+// size returns the estimated size of the file including buffered pages.
+func (pf *partitionFile) size() int64 {
+	return pf.writer.Offset + pf.writer.Size
+}
+
+func (pf *partitionFile) expired(now time.Time) bool {
+	return !pf.expireTime.IsZero() && now.After(pf.expireTime)
+}
+
+// rotate writes the parquet footer and renames the file so that it is
+// picked up by readers.  If the number of archives in the partition exceeds
+// maxArchives, older files are deleted.
+func (pf *partitionFile) rotate(maxArchives int) error {
+	if err := pf.writer.WriteStop(); err != nil {
+		pf.file.Close()
+		return err
+	}
+	if err := pf.file.Close(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	rotatedFilename := fmt.Sprintf(rotatedFilenameTemplate, now.Format(rotate.DateFormat), strconv.FormatInt(now.UnixNano(), 10))
+	if err := os.Rename(filepath.Join(pf.dir, activeFilename), filepath.Join(pf.dir, rotatedFilename)); err != nil {
+		return err
+	}
+
+	return purgeArchives(pf.dir, maxArchives)
+}
+
+func purgeArchives(dir string, maxArchives int) error {
+	if maxArchives == -1 {
+		return nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, fmt.Sprintf(rotatedFilenameTemplate, "*", "*")))
+	if err != nil {
+		return err
+	}
+
+	// if there are more archives than the configured maximum, then purge older files
+	if len(matches) > maxArchives {
+		sort.Strings(matches)
+		for _, filename := range matches[:len(matches)-maxArchives] {
+			if err := os.Remove(filename); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
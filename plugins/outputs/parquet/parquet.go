@@ -0,0 +1,206 @@
+package parquet
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/internal"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+const (
+	defaultPartitionFormat  = "2006-01-02/15"
+	defaultRotationInterval = time.Hour
+)
+
+var sampleConfig = `
+  ## Directory to write the parquet files to.  Files are written to a
+  ## directory per measurement and time partition.
+  directory = "/var/lib/telegraf/parquet"
+
+  ## Time based partitioning of the files below the measurement directory,
+  ## as Go time layout applied to the metric time.  The default creates
+  ## hourly directories like "cpu/2020-11-30/15".
+  # partition_format = "2006-01-02/15"
+
+  ## Timezone used for the partitions.
+  # partition_timezone = "UTC"
+
+  ## Compression codec, one of "none", "snappy", "gzip" or "zstd".
+  # compression = "snappy"
+
+  ## Files only become readable once they are rotated.  The file will be
+  ## rotated after the time interval specified.  When set to 0 no time based
+  ## rotation is performed.
+  # rotation_interval = "1h"
+
+  ## The file will be rotated when it becomes larger than the specified
+  ## size.  When set to 0 no size based rotation is performed.
+  # rotation_max_size = "0MB"
+
+  ## Maximum number of rotated files to keep per partition, any older files
+  ## are deleted.  If set to -1, no files are removed.
+  # rotation_max_archives = -1
+`
+
+type Parquet struct {
+	Directory           string            `toml:"directory"`
+	PartitionFormat     string            `toml:"partition_format"`
+	PartitionTimezone   string            `toml:"partition_timezone"`
+	Compression         string            `toml:"compression"`
+	RotationInterval    internal.Duration `toml:"rotation_interval"`
+	RotationMaxSize     internal.Size     `toml:"rotation_max_size"`
+	RotationMaxArchives int               `toml:"rotation_max_archives"`
+	Log                 telegraf.Logger   `toml:"-"`
+
+	location *time.Location
+	codec    parquet.CompressionCodec
+	files    map[string]*partitionFile
+}
+
+func (p *Parquet) SampleConfig() string {
+	return sampleConfig
+}
+
+func (p *Parquet) Description() string {
+	return "Write metrics to parquet files partitioned by measurement and time"
+}
+
+func (p *Parquet) Init() error {
+	if p.Directory == "" {
+		return fmt.Errorf("directory is required")
+	}
+	if p.PartitionFormat == "" {
+		p.PartitionFormat = defaultPartitionFormat
+	}
+
+	var err error
+	p.location, err = time.LoadLocation(p.PartitionTimezone)
+	if err != nil {
+		return err
+	}
+
+	switch p.Compression {
+	case "none":
+		p.codec = parquet.CompressionCodec_UNCOMPRESSED
+	case "", "snappy":
+		p.codec = parquet.CompressionCodec_SNAPPY
+	case "gzip":
+		p.codec = parquet.CompressionCodec_GZIP
+	case "zstd":
+		p.codec = parquet.CompressionCodec_ZSTD
+	default:
+		return fmt.Errorf("unsupported compression %q", p.Compression)
+	}
+	return nil
+}
+
+func (p *Parquet) Connect() error {
+	p.files = make(map[string]*partitionFile)
+	return nil
+}
+
+func (p *Parquet) Close() error {
+	var errs []string
+	for dir, pf := range p.files {
+		if err := pf.rotate(p.RotationMaxArchives); err != nil {
+			errs = append(errs, err.Error())
+		}
+		delete(p.files, dir)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("closing parquet files: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (p *Parquet) Write(metrics []telegraf.Metric) error {
+	var order []string
+	partitions := make(map[string][]telegraf.Metric)
+	for _, m := range metrics {
+		dir := p.partitionDir(m)
+		if _, ok := partitions[dir]; !ok {
+			order = append(order, dir)
+		}
+		partitions[dir] = append(partitions[dir], m)
+	}
+
+	for _, dir := range order {
+		if err := p.writePartition(dir, partitions[dir]); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	for dir, pf := range p.files {
+		if pf.expired(now) {
+			delete(p.files, dir)
+			if err := pf.rotate(p.RotationMaxArchives); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Parquet) writePartition(dir string, metrics []telegraf.Metric) error {
+	schema := deriveSchema(metrics)
+
+	pf, ok := p.files[dir]
+	if ok && !pf.schema.covers(metrics) {
+		// The schema of a parquet file can't be changed, so start a new file
+		// with the columns of both.
+		p.Log.Debugf("Schema of %q changed, rotating file", dir)
+		schema = pf.schema.merge(schema)
+		delete(p.files, dir)
+		if err := pf.rotate(p.RotationMaxArchives); err != nil {
+			return err
+		}
+		ok = false
+	}
+
+	if !ok {
+		var err error
+		pf, err = openPartitionFile(dir, schema, p.codec, p.RotationInterval.Duration)
+		if err != nil {
+			return err
+		}
+		p.files[dir] = pf
+	}
+
+	for _, m := range metrics {
+		if err := pf.write(m); err != nil {
+			return err
+		}
+	}
+
+	if p.RotationMaxSize.Size > 0 && pf.size() >= p.RotationMaxSize.Size {
+		delete(p.files, dir)
+		return pf.rotate(p.RotationMaxArchives)
+	}
+	return nil
+}
+
+func (p *Parquet) partitionDir(m telegraf.Metric) string {
+	measurement := strings.NewReplacer("/", "_", `\`, "_").Replace(m.Name())
+	if measurement == "." || measurement == ".." {
+		measurement = strings.Repeat("_", len(measurement))
+	}
+	partition := m.Time().In(p.location).Format(p.PartitionFormat)
+	return filepath.Join(p.Directory, measurement, filepath.FromSlash(partition))
+}
+
+func init() {
+	outputs.Add("parquet", func() telegraf.Output {
+		return &Parquet{
+			PartitionFormat:     defaultPartitionFormat,
+			Compression:         "snappy",
+			RotationInterval:    internal.Duration{Duration: defaultRotationInterval},
+			RotationMaxArchives: -1,
+		}
+	})
+}
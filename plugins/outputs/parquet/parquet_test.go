@@ -0,0 +1,252 @@
+package parquet
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/internal"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func newParquet(dir string) *Parquet {
+	return &Parquet{
+		Directory:           dir,
+		PartitionFormat:     defaultPartitionFormat,
+		Compression:         "snappy",
+		RotationMaxArchives: -1,
+		Log:                 testutil.Logger{},
+	}
+}
+
+// readColumns returns the column names and values of a parquet file.
+func readColumns(t *testing.T, filename string) ([]string, [][]interface{}) {
+	file, err := (&localFile{}).Open(filename)
+	require.NoError(t, err)
+	defer file.Close()
+
+	r, err := reader.NewParquetColumnReader(file, 1)
+	require.NoError(t, err)
+	defer r.ReadStop()
+
+	var names []string
+	var columns [][]interface{}
+	numRows := r.GetNumRows()
+	// The reader renames the columns in the footer, the schema handler
+	// keeps the names stored in the file.
+	for i, info := range r.SchemaHandler.Infos[1:] {
+		names = append(names, info.ExName)
+		values, _, _, err := r.ReadColumnByIndex(int64(i), numRows)
+		require.NoError(t, err)
+		columns = append(columns, values)
+	}
+	return names, columns
+}
+
+func rotatedFiles(t *testing.T, dir string) []string {
+	matches, err := filepath.Glob(filepath.Join(dir, "metrics.*.parquet"))
+	require.NoError(t, err)
+	return matches
+}
+
+func TestWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "parquet")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	p := newParquet(dir)
+	require.NoError(t, p.Init())
+	require.NoError(t, p.Connect())
+
+	metrics := []telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"usage": 42.0, "cores": int64(4)},
+			time.Unix(1606744800, 0)),
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "b", "cpu": "cpu0"},
+			map[string]interface{}{"usage": int64(7), "ok": true},
+			time.Unix(1606744860, 0)),
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"usage": 1.5},
+			time.Unix(1606748400, 0)),
+		testutil.MustMetric("disk/io",
+			map[string]string{},
+			map[string]interface{}{"reads": uint64(3)},
+			time.Unix(1606744800, 0)),
+	}
+	require.NoError(t, p.Write(metrics))
+
+	// Files are not readable until they are rotated.
+	require.Empty(t, rotatedFiles(t, filepath.Join(dir, "cpu", "2020-11-30", "14")))
+	require.NoError(t, p.Close())
+
+	files := rotatedFiles(t, filepath.Join(dir, "cpu", "2020-11-30", "14"))
+	require.Len(t, files, 1)
+	names, columns := readColumns(t, files[0])
+	require.Equal(t, []string{"time", "cpu", "host", "cores", "ok", "usage"}, names)
+	require.Equal(t, [][]interface{}{
+		{int64(1606744800000000), int64(1606744860000000)},
+		{nil, "cpu0"},
+		{"a", "b"},
+		{int64(4), nil},
+		{nil, true},
+		{42.0, 7.0},
+	}, columns)
+
+	files = rotatedFiles(t, filepath.Join(dir, "cpu", "2020-11-30", "15"))
+	require.Len(t, files, 1)
+	names, columns = readColumns(t, files[0])
+	require.Equal(t, []string{"time", "host", "usage"}, names)
+	require.Equal(t, [][]interface{}{
+		{int64(1606748400000000)},
+		{"a"},
+		{1.5},
+	}, columns)
+
+	files = rotatedFiles(t, filepath.Join(dir, "disk_io", "2020-11-30", "14"))
+	require.Len(t, files, 1)
+	names, columns = readColumns(t, files[0])
+	require.Equal(t, []string{"time", "reads"}, names)
+	require.Equal(t, [][]interface{}{
+		{int64(1606744800000000)},
+		{int64(3)},
+	}, columns)
+}
+
+func TestSchemaChangeRotates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "parquet")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	p := newParquet(dir)
+	p.PartitionFormat = "2006"
+	require.NoError(t, p.Init())
+	require.NoError(t, p.Connect())
+
+	partition := filepath.Join(dir, "cpu", "2020")
+
+	require.NoError(t, p.Write([]telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"usage": 42.0},
+			time.Unix(1606744800, 0)),
+	}))
+	require.Empty(t, rotatedFiles(t, partition))
+
+	// Values of a known column don't change the schema.
+	require.NoError(t, p.Write([]telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"usage": int64(1)},
+			time.Unix(1606744801, 0)),
+	}))
+	require.Empty(t, rotatedFiles(t, partition))
+
+	require.NoError(t, p.Write([]telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"usage": 43.0},
+			time.Unix(1606744802, 0)),
+	}))
+	files := rotatedFiles(t, partition)
+	require.Len(t, files, 1)
+	names, columns := readColumns(t, files[0])
+	require.Equal(t, []string{"time", "usage"}, names)
+	require.Equal(t, []interface{}{42.0, 1.0}, columns[1])
+
+	require.NoError(t, p.Close())
+	files = rotatedFiles(t, partition)
+	require.Len(t, files, 2)
+	names, columns = readColumns(t, files[1])
+	require.Equal(t, []string{"time", "host", "usage"}, names)
+	require.Equal(t, []interface{}{"a"}, columns[1])
+}
+
+func TestTypeChangeRotates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "parquet")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	p := newParquet(dir)
+	p.PartitionFormat = "2006"
+	require.NoError(t, p.Init())
+	require.NoError(t, p.Connect())
+
+	partition := filepath.Join(dir, "cpu", "2020")
+
+	require.NoError(t, p.Write([]telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"usage": int64(1)},
+			time.Unix(1606744800, 0)),
+	}))
+	require.Empty(t, rotatedFiles(t, partition))
+
+	// A value not fitting the type of the column widens the column type
+	require.NoError(t, p.Write([]telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"usage": 1.5},
+			time.Unix(1606744801, 0)),
+	}))
+	files := rotatedFiles(t, partition)
+	require.Len(t, files, 1)
+	_, columns := readColumns(t, files[0])
+	require.Equal(t, []interface{}{int64(1)}, columns[1])
+
+	require.NoError(t, p.Close())
+	files = rotatedFiles(t, partition)
+	require.Len(t, files, 2)
+	names, columns := readColumns(t, files[1])
+	require.Equal(t, []string{"time", "usage"}, names)
+	require.Equal(t, []interface{}{1.5}, columns[1])
+}
+
+func TestRotationMaxArchives(t *testing.T) {
+	dir, err := ioutil.TempDir("", "parquet")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	p := newParquet(dir)
+	p.RotationMaxSize = internal.Size{Size: 1}
+	p.RotationMaxArchives = 2
+	require.NoError(t, p.Init())
+	require.NoError(t, p.Connect())
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, p.Write([]telegraf.Metric{
+			testutil.MustMetric("cpu",
+				map[string]string{},
+				map[string]interface{}{"usage": float64(i)},
+				time.Unix(1606744800, 0)),
+		}))
+	}
+	require.NoError(t, p.Close())
+
+	files := rotatedFiles(t, filepath.Join(dir, "cpu", "2020-11-30", "14"))
+	require.Len(t, files, 2)
+	_, columns := readColumns(t, files[0])
+	require.Equal(t, []interface{}{2.0}, columns[1])
+	_, columns = readColumns(t, files[1])
+	require.Equal(t, []interface{}{3.0}, columns[1])
+}
+
+func TestInvalidConfig(t *testing.T) {
+	p := newParquet("")
+	require.Error(t, p.Init())
+
+	p = newParquet("/tmp")
+	p.Compression = "lz4"
+	require.Error(t, p.Init())
+
+	p = newParquet("/tmp")
+	p.PartitionTimezone = "Mars/Olympus_Mons"
+	require.Error(t, p.Init())
+}
@@ -0,0 +1,316 @@
+package parquet
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+const (
+	rootName   = "telegraf"
+	timeColumn = "time"
+)
+
+// columnType is the type of the values stored in a column.
+type columnType int
+
+const (
+	typeTag columnType = iota
+	typeDouble
+	typeInt64
+	typeUint64
+	typeString
+	typeBoolean
+)
+
+type column struct {
+	name string
+	typ  columnType
+}
+
+// tableSchema is the schema derived for the metrics of a measurement; the
+// first column is always the metric time, followed by the tags and fields
+// in alphabetical order.
+type tableSchema struct {
+	tags   []column
+	fields []column
+
+	tagIndex   map[string]int
+	fieldIndex map[string]int
+}
+
+func newTableSchema() *tableSchema {
+	return &tableSchema{
+		tagIndex:   make(map[string]int),
+		fieldIndex: make(map[string]int),
+	}
+}
+
+// deriveSchema returns a schema with columns for all tags and fields of the
+// metrics.  If a field has different types the types are widened, see
+// widenType.
+func deriveSchema(metrics []telegraf.Metric) *tableSchema {
+	tags := make(map[string]bool)
+	fields := make(map[string]columnType)
+	for _, m := range metrics {
+		for _, tag := range m.TagList() {
+			tags[tag.Key] = true
+		}
+	}
+	for _, m := range metrics {
+		for _, field := range m.FieldList() {
+			if tags[field.Key] {
+				continue
+			}
+			typ, ok := valueType(field.Value)
+			if !ok {
+				continue
+			}
+			if prev, ok := fields[field.Key]; ok {
+				typ = widenType(prev, typ)
+			}
+			fields[field.Key] = typ
+		}
+	}
+	return buildSchema(tags, fields)
+}
+
+func buildSchema(tags map[string]bool, fields map[string]columnType) *tableSchema {
+	s := newTableSchema()
+	for name := range tags {
+		s.tags = append(s.tags, column{name: name, typ: typeTag})
+	}
+	for name, typ := range fields {
+		s.fields = append(s.fields, column{name: name, typ: typ})
+	}
+	sort.Slice(s.tags, func(i, j int) bool { return s.tags[i].name < s.tags[j].name })
+	sort.Slice(s.fields, func(i, j int) bool { return s.fields[i].name < s.fields[j].name })
+	for i, c := range s.tags {
+		s.tagIndex[c.name] = i
+	}
+	for i, c := range s.fields {
+		s.fieldIndex[c.name] = i
+	}
+	return s
+}
+
+// covers returns true if the schema has a column for every tag and field of
+// the metrics and the field columns can hold the values without widening
+// their type.
+func (s *tableSchema) covers(metrics []telegraf.Metric) bool {
+	for _, m := range metrics {
+		for _, tag := range m.TagList() {
+			if _, ok := s.tagIndex[tag.Key]; !ok {
+				return false
+			}
+		}
+		for _, field := range m.FieldList() {
+			if _, ok := s.tagIndex[field.Key]; ok {
+				continue
+			}
+			typ, ok := valueType(field.Value)
+			if !ok {
+				continue
+			}
+			i, ok := s.fieldIndex[field.Key]
+			if !ok {
+				return false
+			}
+			if widenType(s.fields[i].typ, typ) != s.fields[i].typ {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// merge returns a schema with the columns of both schemas.
+func (s *tableSchema) merge(other *tableSchema) *tableSchema {
+	tags := make(map[string]bool)
+	fields := make(map[string]columnType)
+	for _, schema := range []*tableSchema{s, other} {
+		for _, c := range schema.tags {
+			tags[c.name] = true
+		}
+	}
+	for _, schema := range []*tableSchema{s, other} {
+		for _, c := range schema.fields {
+			if tags[c.name] {
+				continue
+			}
+			if prev, ok := fields[c.name]; ok {
+				fields[c.name] = widenType(prev, c.typ)
+			} else {
+				fields[c.name] = c.typ
+			}
+		}
+	}
+	return buildSchema(tags, fields)
+}
+
+// elements returns the parquet schema.  All columns are optional as metrics
+// of a measurement don't need to share the same tags and fields.
+func (s *tableSchema) elements() []*parquet.SchemaElement {
+	numChildren := int32(1 + len(s.tags) + len(s.fields))
+	root := parquet.NewSchemaElement()
+	root.Name = rootName
+	root.NumChildren = &numChildren
+	root.RepetitionType = parquet.FieldRepetitionTypePtr(parquet.FieldRepetitionType_REQUIRED)
+
+	elements := []*parquet.SchemaElement{
+		root,
+		newElement(timeColumn, parquet.Type_INT64, parquet.ConvertedTypePtr(parquet.ConvertedType_TIMESTAMP_MICROS),
+			parquet.FieldRepetitionType_OPTIONAL),
+	}
+	for _, c := range s.tags {
+		elements = append(elements, newElement(c.name, parquet.Type_BYTE_ARRAY, parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8),
+			parquet.FieldRepetitionType_OPTIONAL))
+	}
+	for _, c := range s.fields {
+		var element *parquet.SchemaElement
+		switch c.typ {
+		case typeDouble:
+			element = newElement(c.name, parquet.Type_DOUBLE, nil, parquet.FieldRepetitionType_OPTIONAL)
+		case typeInt64:
+			element = newElement(c.name, parquet.Type_INT64, parquet.ConvertedTypePtr(parquet.ConvertedType_INT_64),
+				parquet.FieldRepetitionType_OPTIONAL)
+		case typeUint64:
+			element = newElement(c.name, parquet.Type_INT64, parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_64),
+				parquet.FieldRepetitionType_OPTIONAL)
+		case typeBoolean:
+			element = newElement(c.name, parquet.Type_BOOLEAN, nil, parquet.FieldRepetitionType_OPTIONAL)
+		default:
+			element = newElement(c.name, parquet.Type_BYTE_ARRAY, parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8),
+				parquet.FieldRepetitionType_OPTIONAL)
+		}
+		elements = append(elements, element)
+	}
+	return elements
+}
+
+func newElement(name string, typ parquet.Type, convertedType *parquet.ConvertedType, repetition parquet.FieldRepetitionType) *parquet.SchemaElement {
+	numChildren := int32(0)
+	element := parquet.NewSchemaElement()
+	element.Name = name
+	element.NumChildren = &numChildren
+	element.Type = &typ
+	element.RepetitionType = &repetition
+	element.ConvertedType = convertedType
+	return element
+}
+
+// row returns the values of the metric in column order.  Values not
+// matching the column type are converted if possible and left empty
+// otherwise.
+func (s *tableSchema) row(m telegraf.Metric) []interface{} {
+	row := make([]interface{}, 1+len(s.tags)+len(s.fields))
+	row[0] = m.Time().UnixNano() / 1000
+
+	for _, tag := range m.TagList() {
+		if i, ok := s.tagIndex[tag.Key]; ok {
+			row[1+i] = tag.Value
+		}
+	}
+
+	offset := 1 + len(s.tags)
+	for _, field := range m.FieldList() {
+		if i, ok := s.fieldIndex[field.Key]; ok {
+			if v, ok := convertValue(field.Value, s.fields[i].typ); ok {
+				row[offset+i] = v
+			}
+		}
+	}
+	return row
+}
+
+func valueType(v interface{}) (columnType, bool) {
+	switch v.(type) {
+	case float64:
+		return typeDouble, true
+	case int64:
+		return typeInt64, true
+	case uint64:
+		return typeUint64, true
+	case string:
+		return typeString, true
+	case bool:
+		return typeBoolean, true
+	default:
+		return typeString, false
+	}
+}
+
+// widenType returns a type able to hold the values of both types: mixed
+// integers are stored as int64, mixed numbers as double and everything
+// else as string.
+func widenType(a, b columnType) columnType {
+	switch {
+	case a == b:
+		return a
+	case isNumber(a) && isNumber(b) && (a == typeDouble || b == typeDouble):
+		return typeDouble
+	case isNumber(a) && isNumber(b):
+		return typeInt64
+	default:
+		return typeString
+	}
+}
+
+func isNumber(t columnType) bool {
+	return t == typeDouble || t == typeInt64 || t == typeUint64
+}
+
+// convertValue converts the value to the representation used by the parquet
+// writer for the column type.
+func convertValue(v interface{}, typ columnType) (interface{}, bool) {
+	switch typ {
+	case typeDouble:
+		switch value := v.(type) {
+		case float64:
+			return value, true
+		case int64:
+			return float64(value), true
+		case uint64:
+			return float64(value), true
+		}
+	case typeInt64:
+		switch value := v.(type) {
+		case int64:
+			return value, true
+		case uint64:
+			if value <= math.MaxInt64 {
+				return int64(value), true
+			}
+		}
+	case typeUint64:
+		switch value := v.(type) {
+		case uint64:
+			return int64(value), true
+		case int64:
+			if value >= 0 {
+				return value, true
+			}
+		}
+	case typeBoolean:
+		if value, ok := v.(bool); ok {
+			return value, true
+		}
+	case typeString:
+		switch value := v.(type) {
+		case string:
+			return value, true
+		case float64:
+			return strconv.FormatFloat(value, 'g', -1, 64), true
+		case int64:
+			return strconv.FormatInt(value, 10), true
+		case uint64:
+			return strconv.FormatUint(value, 10), true
+		case bool:
+			return strconv.FormatBool(value), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,76 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/outputs"
+)
+
+func newSQL() *SQL {
+	return outputs.Outputs["sql"]().(*SQL)
+}
+
+func TestInitError(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *SQL)
+	}{
+		{
+			name:   "missing driver",
+			modify: func(s *SQL) { s.Driver = "" },
+		},
+		{
+			name:   "missing dsn",
+			modify: func(s *SQL) { s.Dsn = "" },
+		},
+		{
+			name:   "unknown driver",
+			modify: func(s *SQL) { s.Driver = "foo" },
+		},
+		{
+			name:   "invalid table mode",
+			modify: func(s *SQL) { s.TableMode = "narrow" },
+		},
+		{
+			name: "wide without table",
+			modify: func(s *SQL) {
+				s.TableMode = "wide"
+				s.Table = ""
+			},
+		},
+		{
+			name:   "table template without columns",
+			modify: func(s *SQL) { s.TableTemplate = "CREATE TABLE {TABLE}" },
+		},
+		{
+			name:   "update template without column",
+			modify: func(s *SQL) { s.TableUpdateTemplate = "ALTER TABLE {TABLE}" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := newSQL()
+			plugin.Driver = "pgx"
+			plugin.Dsn = "postgres://localhost/test"
+			tt.modify(plugin)
+			require.Error(t, plugin.Init())
+		})
+	}
+}
+
+func TestQuoting(t *testing.T) {
+	plugin := newSQL()
+	plugin.Driver = "postgres"
+	plugin.Dsn = "postgres://localhost/test"
+	require.NoError(t, plugin.Init())
+	require.Equal(t, `"cpu ""total"""`, plugin.quote(`cpu "total"`))
+	require.Equal(t, "$2", plugin.placeholder(2))
+
+	plugin.Driver = "mysql"
+	require.NoError(t, plugin.Init())
+	require.Equal(t, "`cpu``s`", plugin.quote("cpu`s"))
+	require.Equal(t, "?", plugin.placeholder(2))
+}
@@ -0,0 +1,206 @@
+//go:build linux && (386 || amd64 || arm || arm64)
+// +build linux
+// +build 386 amd64 arm arm64
+
+package sql
+
+import (
+	dbsql "database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+)
+
+// dump returns the rows of the table with NULL values omitted.
+func dump(t *testing.T, dsn string, query string) []map[string]interface{} {
+	db, err := dbsql.Open("sqlite", dsn)
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows, err := db.Query(query)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	require.NoError(t, err)
+
+	var result []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		require.NoError(t, rows.Scan(ptrs...))
+
+		row := make(map[string]interface{})
+		for i, name := range columns {
+			if values[i] != nil {
+				row[name] = values[i]
+			}
+		}
+		result = append(result, row)
+	}
+	require.NoError(t, rows.Err())
+	return result
+}
+
+func TestSqliteMeasurementTables(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "metrics.db")
+
+	plugin := newSQL()
+	plugin.Driver = "sqlite"
+	plugin.Dsn = dsn
+	plugin.TimestampColumn = "time"
+	plugin.Log = testutil.Logger{}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+	defer plugin.Close()
+
+	ts := time.Unix(1600000000, 0).UTC()
+	require.NoError(t, plugin.Write([]telegraf.Metric{
+		testutil.MustMetric(
+			"cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"usage": 42.5, "count": int64(3)},
+			ts,
+		),
+		testutil.MustMetric(
+			"mem",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"used": uint64(1024), "ok": true},
+			ts,
+		),
+	}))
+
+	// A new tag and field appearing in a later batch adds the columns
+	require.NoError(t, plugin.Write([]telegraf.Metric{
+		testutil.MustMetric(
+			"cpu",
+			map[string]string{"host": "b", "dc": "eu"},
+			map[string]interface{}{"usage": 1.5, "idle": 98.5},
+			ts.Add(time.Minute),
+		),
+	}))
+
+	require.Equal(t, []map[string]interface{}{
+		{"time": "2020-09-13 12:26:40", "host": "a", "usage": 42.5, "count": int64(3)},
+		{"time": "2020-09-13 12:27:40", "host": "b", "dc": "eu", "usage": 1.5, "idle": 98.5},
+	}, dump(t, dsn, `SELECT * FROM "cpu" ORDER BY "time"`))
+	require.Equal(t, []map[string]interface{}{
+		{"time": "2020-09-13 12:26:40", "host": "a", "used": int64(1024), "ok": int64(1)},
+	}, dump(t, dsn, `SELECT * FROM "mem"`))
+}
+
+func TestSqliteWideTable(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "metrics.db")
+
+	plugin := newSQL()
+	plugin.Driver = "sqlite3"
+	plugin.Dsn = dsn
+	plugin.TableMode = "wide"
+	plugin.Table = "telegraf"
+	plugin.Log = testutil.Logger{}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+	defer plugin.Close()
+
+	ts := time.Unix(1600000000, 0).UTC()
+	require.NoError(t, plugin.Write([]telegraf.Metric{
+		testutil.MustMetric(
+			"cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"value": 42.5},
+			ts,
+		),
+		testutil.MustMetric(
+			"disk",
+			map[string]string{"host": "a", "path": "/"},
+			map[string]interface{}{"value": 10.0, "free": int64(100)},
+			ts,
+		),
+	}))
+
+	require.Equal(t, []map[string]interface{}{
+		{"timestamp": "2020-09-13 12:26:40", "measurement": "cpu", "host": "a", "value": 42.5},
+		{"timestamp": "2020-09-13 12:26:40", "measurement": "disk", "host": "a", "path": "/", "value": 10.0, "free": int64(100)},
+	}, dump(t, dsn, `SELECT * FROM "telegraf" ORDER BY "measurement"`))
+}
+
+func TestSqliteExistingTable(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "metrics.db")
+
+	db, err := dbsql.Open("sqlite", dsn)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE "cpu" ("timestamp" TIMESTAMP, "host" TEXT, "usage" REAL, "note" TEXT)`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	plugin := newSQL()
+	plugin.Driver = "sqlite"
+	plugin.Dsn = dsn
+	plugin.Log = testutil.Logger{}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+	defer plugin.Close()
+
+	ts := time.Unix(1600000000, 0).UTC()
+	require.NoError(t, plugin.Write([]telegraf.Metric{
+		testutil.MustMetric(
+			"cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"usage": 42.5, "idle": 57.5},
+			ts,
+		),
+	}))
+
+	require.Equal(t, []map[string]interface{}{
+		{"timestamp": "2020-09-13 12:26:40", "host": "a", "usage": 42.5, "idle": 57.5},
+	}, dump(t, dsn, `SELECT * FROM "cpu"`))
+}
+
+func TestSqliteTypeChange(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "metrics.db")
+
+	// SQLite stores any value in any column, only the constraint refuses
+	// values not matching the column type like other databases do.
+	db, err := dbsql.Open("sqlite", dsn)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE "cpu" ("timestamp" TIMESTAMP, "value" BIGINT CHECK(typeof("value") = 'integer'))`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	plugin := newSQL()
+	plugin.Driver = "sqlite"
+	plugin.Dsn = dsn
+	plugin.Log = testutil.Logger{}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+	defer plugin.Close()
+
+	ts := time.Unix(1600000000, 0).UTC()
+	require.NoError(t, plugin.Write([]telegraf.Metric{
+		testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"value": int64(42)}, ts),
+	}))
+
+	// The metric with the string value is dropped, the rest of the batch is
+	// written.
+	require.NoError(t, plugin.Write([]telegraf.Metric{
+		testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"value": "high"}, ts.Add(time.Minute)),
+		testutil.MustMetric("cpu", map[string]string{}, map[string]interface{}{"value": int64(43)}, ts.Add(2*time.Minute)),
+		testutil.MustMetric("mem", map[string]string{}, map[string]interface{}{"used": int64(1024)}, ts),
+	}))
+
+	require.Equal(t, []map[string]interface{}{
+		{"timestamp": "2020-09-13 12:26:40", "value": int64(42)},
+		{"timestamp": "2020-09-13 12:28:40", "value": int64(43)},
+	}, dump(t, dsn, `SELECT * FROM "cpu" ORDER BY "timestamp"`))
+	require.Equal(t, []map[string]interface{}{
+		{"timestamp": "2020-09-13 12:26:40", "used": int64(1024)},
+	}, dump(t, dsn, `SELECT * FROM "mem"`))
+}
@@ -0,0 +1,316 @@
+package avro
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/internal"
+	"github.com/shanas-swi/telegraf-v1.16.3/metric"
+)
+
+var (
+	ErrNoMetric     = errors.New("no metric in message")
+	ErrNoSchemaInfo = errors.New("either a schema registry or a schema file is required")
+)
+
+// magicByte is the first byte of every message in the Confluent wire format.
+const magicByte = 0x00
+
+type Config struct {
+	MetricName      string
+	SchemaRegistry  string
+	SchemaFile      string
+	Measurement     string
+	Tags            []string
+	Fields          []string
+	FieldSeparator  string
+	Timestamp       string
+	TimestampFormat string
+	DefaultTags     map[string]string
+}
+
+// Parser decodes Avro encoded records into metrics.  Records are either
+// framed in the Confluent wire format, with the schema looked up in a schema
+// registry, or are plain binary records using a local schema file.
+type Parser struct {
+	metricName      string
+	measurement     string
+	tags            []string
+	fields          []string
+	fieldSeparator  string
+	timestamp       string
+	timestampFormat string
+	defaultTags     map[string]string
+
+	registry *schemaRegistry
+	schema   *schemaAndCodec
+
+	TimeFunc func() time.Time
+}
+
+func New(config *Config) (*Parser, error) {
+	p := &Parser{
+		metricName:      config.MetricName,
+		measurement:     config.Measurement,
+		tags:            config.Tags,
+		fields:          config.Fields,
+		fieldSeparator:  config.FieldSeparator,
+		timestamp:       config.Timestamp,
+		timestampFormat: config.TimestampFormat,
+		defaultTags:     config.DefaultTags,
+		TimeFunc:        time.Now,
+	}
+
+	if p.fieldSeparator == "" {
+		p.fieldSeparator = "_"
+	}
+
+	if p.timestampFormat == "" {
+		p.timestampFormat = "unix"
+	}
+
+	switch {
+	case config.SchemaFile != "":
+		buf, err := ioutil.ReadFile(config.SchemaFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading avro schema file: %v", err)
+		}
+		p.schema, err = newSchemaAndCodec(string(buf))
+		if err != nil {
+			return nil, fmt.Errorf("compiling avro schema: %v", err)
+		}
+	case config.SchemaRegistry != "":
+		p.registry = newSchemaRegistry(config.SchemaRegistry)
+	default:
+		return nil, ErrNoSchemaInfo
+	}
+
+	return p, nil
+}
+
+// Parse decodes a single Avro record into a metric.
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	schema := p.schema
+	message := buf
+	if schema == nil {
+		if len(buf) < 5 || buf[0] != magicByte {
+			return nil, errors.New("message is not in the schema registry wire format")
+		}
+
+		var err error
+		schemaID := int(binary.BigEndian.Uint32(buf[1:5]))
+		schema, err = p.registry.getSchemaAndCodec(schemaID)
+		if err != nil {
+			return nil, err
+		}
+		message = buf[5:]
+	}
+
+	native, _, err := schema.Codec.NativeFromBinary(message)
+	if err != nil {
+		return nil, err
+	}
+
+	record, ok := native.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("top-level avro type must be a record, got %T", native)
+	}
+
+	m, err := p.createMetric(record, schema)
+	if err != nil {
+		return nil, err
+	}
+	return []telegraf.Metric{m}, nil
+}
+
+// ParseLine is not useful for a binary format but is required by the
+// interface; the line is treated as a complete message.
+func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
+	metrics, err := p.Parse([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metrics) < 1 {
+		return nil, ErrNoMetric
+	}
+	return metrics[0], nil
+}
+
+// SetDefaultTags adds tags to the metrics outputs of Parse and ParseLine.
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.defaultTags = tags
+}
+
+func (p *Parser) createMetric(record map[string]interface{}, schema *schemaAndCodec) (telegraf.Metric, error) {
+	flat := make(map[string]interface{})
+	p.flatten(flat, "", record, schema, schema.Root, "")
+
+	name := p.metricName
+	if p.measurement != "" {
+		name = p.measurement
+	}
+
+	tags := make(map[string]string)
+	for k, v := range p.defaultTags {
+		tags[k] = v
+	}
+	for _, key := range p.tags {
+		if value, ok := flat[key]; ok {
+			tags[key] = tagValue(value)
+		}
+	}
+
+	fields := make(map[string]interface{})
+	if len(p.fields) > 0 {
+		for _, key := range p.fields {
+			if value, ok := flat[key]; ok {
+				fields[key] = fieldValue(value)
+			}
+		}
+	} else {
+		for key, value := range flat {
+			if _, ok := tags[key]; ok || key == p.timestamp {
+				continue
+			}
+			fields[key] = fieldValue(value)
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, ErrNoMetric
+	}
+
+	timestamp := p.TimeFunc()
+	if p.timestamp != "" {
+		value, ok := flat[p.timestamp]
+		if !ok {
+			return nil, fmt.Errorf("timestamp field %q not found", p.timestamp)
+		}
+
+		var err error
+		timestamp, err = p.parseTimestamp(value)
+		if err != nil {
+			return nil, fmt.Errorf("parsing timestamp field %q: %v", p.timestamp, err)
+		}
+	}
+
+	return metric.New(name, tags, fields, timestamp)
+}
+
+func (p *Parser) parseTimestamp(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case int32:
+		value = int64(v)
+	case float32:
+		value = float64(v)
+	}
+	return internal.ParseTimestamp(p.timestampFormat, value, "")
+}
+
+// flatten converts the native goavro representation into a flat map of field
+// values.  Nested records are joined with the field separator and union
+// wrappers are removed.  The value is interpreted by its type in the schema,
+// as the keys of union wrappers can't be told apart from record fields or
+// map entries.
+func (p *Parser) flatten(dst map[string]interface{}, prefix string, value interface{}, sc *schemaAndCodec, schema interface{}, namespace string) {
+	schema, namespace = sc.resolveType(schema, namespace)
+	switch s := schema.(type) {
+	case []interface{}:
+		if v, ok := value.(map[string]interface{}); ok && len(v) == 1 {
+			for key, inner := range v {
+				p.flatten(dst, prefix, inner, sc, unionBranch(s, key, namespace), namespace)
+			}
+			return
+		}
+	case map[string]interface{}:
+		switch s["type"] {
+		case "record", "error":
+			record, ok := value.(map[string]interface{})
+			if !ok {
+				break
+			}
+			fields, _ := s["fields"].([]interface{})
+			for _, field := range fields {
+				f, _ := field.(map[string]interface{})
+				name, _ := f["name"].(string)
+				if inner, ok := record[name]; ok {
+					p.flatten(dst, p.join(prefix, name), inner, sc, f["type"], namespace)
+				}
+			}
+			return
+		case "array":
+			if v, ok := value.([]interface{}); ok {
+				for i, inner := range v {
+					p.flatten(dst, p.join(prefix, strconv.Itoa(i)), inner, sc, s["items"], namespace)
+				}
+				return
+			}
+		case "map":
+			if v, ok := value.(map[string]interface{}); ok {
+				for key, inner := range v {
+					p.flatten(dst, p.join(prefix, key), inner, sc, s["values"], namespace)
+				}
+				return
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, inner := range v {
+			p.flatten(dst, p.join(prefix, key), inner, sc, nil, namespace)
+		}
+	case []interface{}:
+		for i, inner := range v {
+			p.flatten(dst, p.join(prefix, strconv.Itoa(i)), inner, sc, nil, namespace)
+		}
+	case nil:
+	case int32:
+		dst[prefix] = int64(v)
+	case float32:
+		dst[prefix] = float64(v)
+	case []byte:
+		dst[prefix] = string(v)
+	case time.Duration:
+		dst[prefix] = int64(v)
+	case *big.Rat:
+		f, _ := v.Float64()
+		dst[prefix] = f
+	default:
+		dst[prefix] = v
+	}
+}
+
+func (p *Parser) join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + p.fieldSeparator + key
+}
+
+func tagValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func fieldValue(value interface{}) interface{} {
+	if t, ok := value.(time.Time); ok {
+		return t.UnixNano()
+	}
+	return value
+}
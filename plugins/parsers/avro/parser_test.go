@@ -0,0 +1,276 @@
+package avro
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var record = map[string]interface{}{
+	"tag":      "test_tag",
+	"field":    int64(19),
+	"optional": goavro.Union("double", 4.5),
+	"nested": map[string]interface{}{
+		"a": int32(1),
+		"b": "two",
+	},
+	"timestamp": int64(1664296121),
+}
+
+func encode(t *testing.T, schema string, native interface{}) []byte {
+	t.Helper()
+	codec, err := goavro.NewCodec(schema)
+	require.NoError(t, err)
+	buf, err := codec.BinaryFromNative(nil, native)
+	require.NoError(t, err)
+	return buf
+}
+
+func readSchema(t *testing.T) string {
+	t.Helper()
+	buf, err := ioutil.ReadFile("testdata/value.avsc")
+	require.NoError(t, err)
+	return string(buf)
+}
+
+func TestSchemaFile(t *testing.T) {
+	parser, err := New(&Config{
+		MetricName:  "avro",
+		SchemaFile:  "testdata/value.avsc",
+		Measurement: "measurement",
+		Tags:        []string{"tag"},
+		Timestamp:   "timestamp",
+	})
+	require.NoError(t, err)
+
+	metrics, err := parser.Parse(encode(t, readSchema(t), record))
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"measurement",
+			map[string]string{
+				"tag": "test_tag",
+			},
+			map[string]interface{}{
+				"field":    int64(19),
+				"optional": 4.5,
+				"nested_a": int64(1),
+				"nested_b": "two",
+			},
+			time.Unix(1664296121, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func TestSelectedFieldsAndNullUnion(t *testing.T) {
+	parser, err := New(&Config{
+		MetricName:     "avro",
+		SchemaFile:     "testdata/value.avsc",
+		Fields:         []string{"field", "optional", "nested.a"},
+		FieldSeparator: ".",
+	})
+	require.NoError(t, err)
+	parser.TimeFunc = func() time.Time { return time.Unix(42, 0) }
+
+	native := map[string]interface{}{}
+	for k, v := range record {
+		native[k] = v
+	}
+	native["optional"] = nil
+
+	metrics, err := parser.Parse(encode(t, readSchema(t), native))
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"avro",
+			map[string]string{},
+			map[string]interface{}{
+				"field":    int64(19),
+				"nested.a": int64(1),
+			},
+			time.Unix(42, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func TestSchemaRegistry(t *testing.T) {
+	schema := readSchema(t)
+
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/schemas/ids/3" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"schema": %s}`, strconv.Quote(schema))
+	}))
+	defer ts.Close()
+
+	parser, err := New(&Config{
+		MetricName:     "avro",
+		SchemaRegistry: ts.URL,
+		Tags:           []string{"tag", "nested_b"},
+		Timestamp:      "timestamp",
+	})
+	require.NoError(t, err)
+
+	header := make([]byte, 5)
+	binary.BigEndian.PutUint32(header[1:], 3)
+	message := append(header, encode(t, schema, record)...)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"avro",
+			map[string]string{
+				"tag":      "test_tag",
+				"nested_b": "two",
+			},
+			map[string]interface{}{
+				"field":    int64(19),
+				"optional": 4.5,
+				"nested_a": int64(1),
+			},
+			time.Unix(1664296121, 0),
+		),
+	}
+
+	for i := 0; i < 2; i++ {
+		metrics, err := parser.Parse(message)
+		require.NoError(t, err)
+		testutil.RequireMetricsEqual(t, expected, metrics)
+	}
+	require.Equal(t, 1, requests)
+
+	binary.BigEndian.PutUint32(message[1:], 4)
+	_, err = parser.Parse(message)
+	require.Error(t, err)
+}
+
+func TestWireFormatRequired(t *testing.T) {
+	parser, err := New(&Config{
+		MetricName:     "avro",
+		SchemaRegistry: "http://localhost:8081",
+	})
+	require.NoError(t, err)
+
+	_, err = parser.Parse([]byte{0x01, 0x02})
+	require.Error(t, err)
+}
+
+func TestNoSchemaInfo(t *testing.T) {
+	_, err := New(&Config{MetricName: "avro"})
+	require.Equal(t, ErrNoSchemaInfo, err)
+}
+
+func TestNullableLogicalType(t *testing.T) {
+	schema := `{
+		"type": "record",
+		"name": "Value",
+		"fields": [
+			{"name": "value", "type": "double"},
+			{"name": "ts", "type": ["null", {"type": "long", "logicalType": "timestamp-millis"}]},
+			{"name": "day", "type": ["null", {"type": "int", "logicalType": "date"}]}
+		]
+	}`
+
+	parser, err := New(&Config{
+		MetricName: "avro",
+		SchemaFile: writeSchema(t, schema),
+		Timestamp:  "ts",
+	})
+	require.NoError(t, err)
+
+	native := map[string]interface{}{
+		"value": 1.5,
+		"ts":    goavro.Union("long.timestamp-millis", time.Unix(1664296121, 0)),
+		"day":   goavro.Union("int.date", time.Unix(1664236800, 0)),
+	}
+	metrics, err := parser.Parse(encode(t, schema, native))
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"avro",
+			map[string]string{},
+			map[string]interface{}{
+				"value": 1.5,
+				"day":   time.Unix(1664236800, 0).UnixNano(),
+			},
+			time.Unix(1664296121, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func TestFieldNamedLikeType(t *testing.T) {
+	// Records and maps with a single entry named like a type are not unions
+	schema := `{
+		"type": "record",
+		"name": "Value",
+		"fields": [
+			{"name": "sizes", "type": {
+				"type": "record",
+				"name": "Sizes",
+				"fields": [{"name": "long", "type": "long"}]
+			}},
+			{"name": "labels", "type": {"type": "map", "values": "string"}},
+			{"name": "optional", "type": ["null", "Sizes"]}
+		]
+	}`
+
+	parser, err := New(&Config{
+		MetricName: "avro",
+		SchemaFile: writeSchema(t, schema),
+	})
+	require.NoError(t, err)
+	parser.TimeFunc = func() time.Time { return time.Unix(42, 0) }
+
+	native := map[string]interface{}{
+		"sizes":    map[string]interface{}{"long": int64(7)},
+		"labels":   map[string]interface{}{"Sizes": "x"},
+		"optional": goavro.Union("Sizes", map[string]interface{}{"long": int64(8)}),
+	}
+	metrics, err := parser.Parse(encode(t, schema, native))
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"avro",
+			map[string]string{},
+			map[string]interface{}{
+				"sizes_long":    int64(7),
+				"labels_Sizes":  "x",
+				"optional_long": int64(8),
+			},
+			time.Unix(42, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func writeSchema(t *testing.T, schema string) string {
+	t.Helper()
+	f, err := ioutil.TempFile("", "schema*.avsc")
+	require.NoError(t, err)
+	defer f.Close()
+	t.Cleanup(func() { os.Remove(f.Name()) })
+	_, err = f.WriteString(schema)
+	require.NoError(t, err)
+	return f.Name()
+}
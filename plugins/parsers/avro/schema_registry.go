@@ -0,0 +1,241 @@
+package avro
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+// schemaAndCodec holds a compiled schema together with the parsed schema and
+// its named types, used to interpret the decoded data.
+type schemaAndCodec struct {
+	Schema string
+	Codec  *goavro.Codec
+	Root   interface{}
+	Names  map[string]namedType
+}
+
+// schemaRegistry fetches schemas from a Confluent compatible schema registry
+// and caches them by their ID.
+type schemaRegistry struct {
+	url    string
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[int]*schemaAndCodec
+}
+
+func newSchemaRegistry(url string) *schemaRegistry {
+	return &schemaRegistry{
+		url: strings.TrimRight(url, "/"),
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		cache: make(map[int]*schemaAndCodec),
+	}
+}
+
+func (r *schemaRegistry) getSchemaAndCodec(id int) (*schemaAndCodec, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if sc, ok := r.cache[id]; ok {
+		return sc, nil
+	}
+
+	resp, err := r.client.Get(fmt.Sprintf("%s/schemas/ids/%d", r.url, id))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("schema registry returned status %q for schema id %d", resp.Status, id)
+	}
+
+	var body struct {
+		Schema string `json:"schema"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decoding schema registry response: %v", err)
+	}
+
+	sc, err := newSchemaAndCodec(body.Schema)
+	if err != nil {
+		return nil, err
+	}
+	r.cache[id] = sc
+	return sc, nil
+}
+
+func newSchemaAndCodec(schema string) (*schemaAndCodec, error) {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	// The original schema is used instead of the parsing canonical form as
+	// the latter drops the logical types, which are part of the union keys.
+	var root interface{}
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]namedType)
+	collectNamedTypes(root, "", names)
+
+	return &schemaAndCodec{Schema: schema, Codec: codec, Root: root, Names: names}, nil
+}
+
+var primitiveTypes = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"int":     true,
+	"long":    true,
+	"float":   true,
+	"double":  true,
+	"bytes":   true,
+	"string":  true,
+}
+
+// namedType is the definition of a record, enum or fixed type together with
+// the namespace the names inside of it are resolved in.
+type namedType struct {
+	definition map[string]interface{}
+	namespace  string
+}
+
+// fullName returns the full name of a type name used in the given namespace.
+func fullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// definitionNamespace returns the namespace of a named type definition.
+func definitionNamespace(definition map[string]interface{}, namespace string) string {
+	name, _ := definition["name"].(string)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i]
+	}
+	if ns, ok := definition["namespace"].(string); ok {
+		return ns
+	}
+	return namespace
+}
+
+// isNamedDefinition returns true if the schema defines a record, enum or
+// fixed type.
+func isNamedDefinition(schema map[string]interface{}) bool {
+	switch schema["type"] {
+	case "record", "error", "enum", "fixed":
+		return true
+	}
+	return false
+}
+
+// collectNamedTypes walks a schema and records the definitions of all named
+// types by their full names, so references to them can be resolved.
+func collectNamedTypes(schema interface{}, namespace string, names map[string]namedType) {
+	switch s := schema.(type) {
+	case []interface{}:
+		for _, item := range s {
+			collectNamedTypes(item, namespace, names)
+		}
+	case map[string]interface{}:
+		if isNamedDefinition(s) {
+			name, _ := s["name"].(string)
+			namespace = definitionNamespace(s, namespace)
+			names[fullName(name, namespace)] = namedType{definition: s, namespace: namespace}
+		}
+		if fields, ok := s["fields"].([]interface{}); ok {
+			for _, field := range fields {
+				if f, ok := field.(map[string]interface{}); ok {
+					collectNamedTypes(f["type"], namespace, names)
+				}
+			}
+		}
+		for _, key := range []string{"type", "items", "values"} {
+			if v, ok := s[key]; ok {
+				collectNamedTypes(v, namespace, names)
+			}
+		}
+	}
+}
+
+// resolveType returns the definition of a type in the schema, references to
+// named types are replaced by their definitions.  The returned namespace is
+// the one the names inside of the type are resolved in.
+func (s *schemaAndCodec) resolveType(schema interface{}, namespace string) (interface{}, string) {
+	switch t := schema.(type) {
+	case string:
+		if named, ok := s.Names[fullName(t, namespace)]; ok {
+			return named.definition, named.namespace
+		}
+		if named, ok := s.Names[t]; ok {
+			return named.definition, named.namespace
+		}
+	case map[string]interface{}:
+		if isNamedDefinition(t) {
+			return t, definitionNamespace(t, namespace)
+		}
+		switch inner := t["type"].(type) {
+		case string:
+			if inner == "array" || inner == "map" || primitiveTypes[inner] {
+				return t, namespace
+			}
+			return s.resolveType(inner, namespace)
+		case map[string]interface{}, []interface{}:
+			return s.resolveType(inner, namespace)
+		}
+	}
+	return schema, namespace
+}
+
+// unionKey returns the key goavro uses for values of a union branch.
+func unionKey(branch interface{}, namespace string) string {
+	switch b := branch.(type) {
+	case string:
+		if primitiveTypes[b] {
+			return b
+		}
+		return fullName(b, namespace)
+	case map[string]interface{}:
+		if isNamedDefinition(b) {
+			name, _ := b["name"].(string)
+			return fullName(name, definitionNamespace(b, namespace))
+		}
+		switch t := b["type"].(type) {
+		case string:
+			if lt, ok := b["logicalType"].(string); ok && primitiveTypes[t] {
+				return t + "." + lt
+			}
+			return unionKey(t, namespace)
+		case map[string]interface{}:
+			return unionKey(t, namespace)
+		}
+	}
+	return ""
+}
+
+// unionBranch returns the schema of the union branch with the given key.
+func unionBranch(branches []interface{}, key, namespace string) interface{} {
+	for _, branch := range branches {
+		if unionKey(branch, namespace) == key {
+			return branch
+		}
+	}
+	// Logical types unknown to goavro are keyed by their underlying type
+	for _, branch := range branches {
+		if primitiveTypes[key] && strings.HasPrefix(unionKey(branch, namespace), key+".") {
+			return branch
+		}
+	}
+	return nil
+}
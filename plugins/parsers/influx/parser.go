@@ -64,6 +64,7 @@ type Parser struct {
 	sync.Mutex
 	*machine
 	handler *MetricHandler
+	schema  *Schema
 }
 
 // NewParser returns a Parser than accepts line protocol
@@ -86,6 +87,11 @@ func (h *Parser) SetTimeFunc(f TimeFunc) {
 	h.handler.SetTimeFunc(f)
 }
 
+// SetSchema sets the schema used to validate parsed metrics.
+func (p *Parser) SetSchema(schema *Schema) {
+	p.schema = schema
+}
+
 func (p *Parser) Parse(input []byte) ([]telegraf.Metric, error) {
 	p.Lock()
 	defer p.Unlock()
@@ -118,6 +124,16 @@ func (p *Parser) Parse(input []byte) ([]telegraf.Metric, error) {
 			continue
 		}
 
+		if p.schema != nil {
+			keep, err := p.schema.Apply(metric)
+			if err != nil {
+				return nil, err
+			}
+			if !keep {
+				continue
+			}
+		}
+
 		metrics = append(metrics, metric)
 	}
 
@@ -165,6 +181,7 @@ func (p *Parser) applyDefaultTagsSingle(metric telegraf.Metric) {
 type StreamParser struct {
 	machine *streamMachine
 	handler *MetricHandler
+	schema  *Schema
 }
 
 func NewStreamParser(r io.Reader) *StreamParser {
@@ -186,9 +203,33 @@ func (h *StreamParser) SetTimePrecision(u time.Duration) {
 	h.handler.SetTimePrecision(u)
 }
 
+// SetSchema sets the schema used to validate parsed metrics.  Metrics
+// rejected by the schema are returned as a SchemaError by Next.
+func (p *StreamParser) SetSchema(schema *Schema) {
+	p.schema = schema
+}
+
 // Next parses the next item from the stream.  You can repeat calls to this
-// function if it returns ParseError to get the next metric or error.
+// function if it returns ParseError or SchemaError to get the next metric or
+// error.
 func (p *StreamParser) Next() (telegraf.Metric, error) {
+	for {
+		metric, err := p.next()
+		if err != nil || p.schema == nil {
+			return metric, err
+		}
+
+		keep, err := p.schema.Apply(metric)
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			return metric, nil
+		}
+	}
+}
+
+func (p *StreamParser) next() (telegraf.Metric, error) {
 	err := p.machine.Next()
 	if err == EOF {
 		return nil, err
@@ -0,0 +1,278 @@
+package influx
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/influxdata/toml"
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/selfstat"
+)
+
+// Schema actions decide what happens to metrics not matching the schema.
+const (
+	// SchemaActionCoerce converts mismatching fields to the declared type.
+	// Fields that cannot be converted are dropped.
+	SchemaActionCoerce = "coerce"
+	// SchemaActionDrop removes mismatching fields.
+	SchemaActionDrop = "drop"
+	// SchemaActionReject rejects the whole metric with a SchemaError.
+	SchemaActionReject = "reject"
+)
+
+// MeasurementSchema describes the expected tags and field types of a
+// measurement.  Field types are one of "float", "integer", "unsigned",
+// "string" or "boolean".
+type MeasurementSchema struct {
+	RequiredTags []string          `toml:"required_tags"`
+	Fields       map[string]string `toml:"fields"`
+}
+
+// SchemaError is returned for metrics rejected by the schema.
+type SchemaError struct {
+	Measurement string
+	msg         string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("metric %q does not match schema: %s", e.Measurement, e.msg)
+}
+
+// Schema validates parsed metrics against the declared field types and
+// required tags of their measurement.  Measurements and fields not present
+// in the schema are passed through unchanged.
+type Schema struct {
+	Measurements map[string]*MeasurementSchema
+	Action       string
+
+	fieldsCoerced   selfstat.Stat
+	fieldsDropped   selfstat.Stat
+	metricsDropped  selfstat.Stat
+	metricsRejected selfstat.Stat
+}
+
+// LoadSchema reads a schema file.  The file is TOML with a table per
+// measurement, for example:
+//
+//	[cpu]
+//	  required_tags = ["host"]
+//	  [cpu.fields]
+//	    usage_idle = "float"
+func LoadSchema(path string, action string) (*Schema, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	measurements := make(map[string]*MeasurementSchema)
+	if err := toml.Unmarshal(buf, &measurements); err != nil {
+		return nil, fmt.Errorf("parsing schema %q: %v", path, err)
+	}
+	return NewSchema(measurements, action, map[string]string{"schema_file": path})
+}
+
+// NewSchema creates a schema for the given measurements; the tags are used
+// for the internal statistics of the schema.
+func NewSchema(measurements map[string]*MeasurementSchema, action string, tags map[string]string) (*Schema, error) {
+	switch action {
+	case "":
+		action = SchemaActionCoerce
+	case SchemaActionCoerce, SchemaActionDrop, SchemaActionReject:
+	default:
+		return nil, fmt.Errorf("unknown schema action %q", action)
+	}
+
+	for name, ms := range measurements {
+		for field, typ := range ms.Fields {
+			switch typ {
+			case "float", "integer", "unsigned", "string", "boolean":
+			default:
+				return nil, fmt.Errorf("unknown type %q for field %q of measurement %q", typ, field, name)
+			}
+		}
+	}
+
+	return &Schema{
+		Measurements:    measurements,
+		Action:          action,
+		fieldsCoerced:   selfstat.Register("influx_schema", "fields_coerced", tags),
+		fieldsDropped:   selfstat.Register("influx_schema", "fields_dropped", tags),
+		metricsDropped:  selfstat.Register("influx_schema", "metrics_dropped", tags),
+		metricsRejected: selfstat.Register("influx_schema", "metrics_rejected", tags),
+	}, nil
+}
+
+// Apply validates the metric against the schema.  It returns false if the
+// metric was dropped, and a SchemaError if it was rejected.
+func (s *Schema) Apply(m telegraf.Metric) (bool, error) {
+	ms, ok := s.Measurements[m.Name()]
+	if !ok {
+		return true, nil
+	}
+
+	for _, tag := range ms.RequiredTags {
+		if !m.HasTag(tag) {
+			return s.fail(m, fmt.Sprintf("missing required tag %q", tag))
+		}
+	}
+
+	var mismatches []string
+	for _, field := range m.FieldList() {
+		typ, ok := ms.Fields[field.Key]
+		if !ok || fieldType(field.Value) == typ {
+			continue
+		}
+		mismatches = append(mismatches, field.Key)
+	}
+	if len(mismatches) == 0 {
+		return true, nil
+	}
+	sort.Strings(mismatches)
+
+	switch s.Action {
+	case SchemaActionReject:
+		field := mismatches[0]
+		value, _ := m.GetField(field)
+		return s.fail(m, fmt.Sprintf("field %q has type %s, expected %s",
+			field, fieldType(value), ms.Fields[field]))
+	case SchemaActionCoerce:
+		for _, field := range mismatches {
+			value, _ := m.GetField(field)
+			if v, ok := coerce(value, ms.Fields[field]); ok {
+				m.AddField(field, v)
+				s.fieldsCoerced.Incr(1)
+			} else {
+				m.RemoveField(field)
+				s.fieldsDropped.Incr(1)
+			}
+		}
+	default:
+		for _, field := range mismatches {
+			m.RemoveField(field)
+			s.fieldsDropped.Incr(1)
+		}
+	}
+
+	if len(m.FieldList()) == 0 {
+		s.metricsDropped.Incr(1)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (s *Schema) fail(m telegraf.Metric, msg string) (bool, error) {
+	if s.Action == SchemaActionReject {
+		s.metricsRejected.Incr(1)
+		return false, &SchemaError{Measurement: m.Name(), msg: msg}
+	}
+	s.metricsDropped.Incr(1)
+	return false, nil
+}
+
+func fieldType(v interface{}) string {
+	switch v.(type) {
+	case float64:
+		return "float"
+	case int64:
+		return "integer"
+	case uint64:
+		return "unsigned"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// coerce converts the value to the given type; conversions that would lose
+// information are refused.
+func coerce(v interface{}, typ string) (interface{}, bool) {
+	switch typ {
+	case "float":
+		switch value := v.(type) {
+		case int64:
+			return float64(value), true
+		case uint64:
+			return float64(value), true
+		case string:
+			f, err := strconv.ParseFloat(value, 64)
+			return f, err == nil
+		case bool:
+			if value {
+				return 1.0, true
+			}
+			return 0.0, true
+		}
+	case "integer":
+		switch value := v.(type) {
+		case float64:
+			if value != math.Trunc(value) || value < math.MinInt64 || value >= math.MaxInt64 {
+				return nil, false
+			}
+			return int64(value), true
+		case uint64:
+			if value > math.MaxInt64 {
+				return nil, false
+			}
+			return int64(value), true
+		case string:
+			i, err := strconv.ParseInt(value, 10, 64)
+			return i, err == nil
+		case bool:
+			if value {
+				return int64(1), true
+			}
+			return int64(0), true
+		}
+	case "unsigned":
+		switch value := v.(type) {
+		case float64:
+			if value != math.Trunc(value) || value < 0 || value >= math.MaxUint64 {
+				return nil, false
+			}
+			return uint64(value), true
+		case int64:
+			if value < 0 {
+				return nil, false
+			}
+			return uint64(value), true
+		case string:
+			u, err := strconv.ParseUint(value, 10, 64)
+			return u, err == nil
+		case bool:
+			if value {
+				return uint64(1), true
+			}
+			return uint64(0), true
+		}
+	case "string":
+		switch value := v.(type) {
+		case float64:
+			return strconv.FormatFloat(value, 'g', -1, 64), true
+		case int64:
+			return strconv.FormatInt(value, 10), true
+		case uint64:
+			return strconv.FormatUint(value, 10), true
+		case bool:
+			return strconv.FormatBool(value), true
+		}
+	case "boolean":
+		switch value := v.(type) {
+		case string:
+			b, err := strconv.ParseBool(value)
+			return b, err == nil
+		case int64:
+			return value != 0, value == 0 || value == 1
+		case uint64:
+			return value != 0, value == 0 || value == 1
+		case float64:
+			return value != 0, value == 0 || value == 1
+		}
+	}
+	return nil, false
+}
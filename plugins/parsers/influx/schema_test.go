@@ -0,0 +1,150 @@
+package influx
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	input := `cpu,host=a usage=1i,cores=4i 0
+cpu,host=b usage=2.5,cores=1.5,state="ok",extra=1i 0
+cpu,host=c usage="high" 0
+cpu usage=1.0 0
+mem used=1i 0
+`
+
+	tests := []struct {
+		name     string
+		action   string
+		expected []telegraf.Metric
+		err      string
+	}{
+		{
+			name:   "coerce",
+			action: SchemaActionCoerce,
+			expected: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"usage": 1.0, "cores": int64(4)},
+					time.Unix(0, 0)),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "b"},
+					map[string]interface{}{"usage": 2.5, "state": "ok", "extra": int64(1)},
+					time.Unix(0, 0)),
+				testutil.MustMetric("mem",
+					map[string]string{},
+					map[string]interface{}{"used": int64(1)},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:   "drop",
+			action: SchemaActionDrop,
+			expected: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"cores": int64(4)},
+					time.Unix(0, 0)),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "b"},
+					map[string]interface{}{"usage": 2.5, "state": "ok", "extra": int64(1)},
+					time.Unix(0, 0)),
+				testutil.MustMetric("mem",
+					map[string]string{},
+					map[string]interface{}{"used": int64(1)},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:   "reject",
+			action: SchemaActionReject,
+			err:    `metric "cpu" does not match schema: field "usage" has type integer, expected float`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := LoadSchema("testdata/schema.toml", tt.action)
+			require.NoError(t, err)
+
+			parser := NewParser(NewMetricHandler())
+			parser.SetSchema(schema)
+
+			metrics, err := parser.Parse([]byte(input))
+			if tt.err != "" {
+				require.IsType(t, &SchemaError{}, err)
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, metrics)
+		})
+	}
+}
+
+func TestStreamParserSchema(t *testing.T) {
+	schema, err := LoadSchema("testdata/schema.toml", SchemaActionReject)
+	require.NoError(t, err)
+
+	parser := NewStreamParser(bytes.NewBufferString("cpu usage=1.0 0\ncpu,host=a usage=1i 0\ncpu,host=a usage=1.0 0\n"))
+	parser.SetSchema(schema)
+
+	_, err = parser.Next()
+	require.EqualError(t, err, `metric "cpu" does not match schema: missing required tag "host"`)
+
+	_, err = parser.Next()
+	require.EqualError(t, err, `metric "cpu" does not match schema: field "usage" has type integer, expected float`)
+
+	m, err := parser.Next()
+	require.NoError(t, err)
+	testutil.RequireMetricEqual(t,
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"usage": 1.0},
+			time.Unix(0, 0)),
+		m)
+
+	_, err = parser.Next()
+	require.Equal(t, EOF, err)
+}
+
+func TestSchemaCoerce(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		typ      string
+		expected interface{}
+		ok       bool
+	}{
+		{value: int64(1), typ: "float", expected: 1.0, ok: true},
+		{value: "2.5", typ: "float", expected: 2.5, ok: true},
+		{value: 1.0, typ: "integer", expected: int64(1), ok: true},
+		{value: 1.5, typ: "integer", ok: false},
+		{value: int64(-1), typ: "unsigned", ok: false},
+		{value: int64(42), typ: "string", expected: "42", ok: true},
+		{value: "true", typ: "boolean", expected: true, ok: true},
+		{value: int64(2), typ: "boolean", ok: false},
+	}
+
+	for _, tt := range tests {
+		v, ok := coerce(tt.value, tt.typ)
+		require.Equal(t, tt.ok, ok, "%v to %s", tt.value, tt.typ)
+		if tt.ok {
+			require.Equal(t, tt.expected, v)
+		}
+	}
+}
+
+func TestSchemaInvalid(t *testing.T) {
+	_, err := NewSchema(map[string]*MeasurementSchema{}, "ignore", nil)
+	require.Error(t, err)
+
+	_, err = NewSchema(map[string]*MeasurementSchema{
+		"cpu": {Fields: map[string]string{"usage": "double"}},
+	}, "", nil)
+	require.Error(t, err)
+}
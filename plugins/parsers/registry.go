@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/parsers/avro"
 	"github.com/shanas-swi/telegraf-v1.16.3/plugins/parsers/collectd"
 	"github.com/shanas-swi/telegraf-v1.16.3/plugins/parsers/csv"
 	"github.com/shanas-swi/telegraf-v1.16.3/plugins/parsers/dropwizard"
@@ -148,6 +149,20 @@ type Config struct {
 
 	// FormData configuration
 	FormUrlencodedTagKeys []string `toml:"form_urlencoded_tag_keys"`
+
+	// influx configuration
+	InfluxSchemaFile   string `toml:"influx_schema_file"`
+	InfluxSchemaAction string `toml:"influx_schema_action"`
+
+	// avro configuration
+	AvroSchemaRegistry  string   `toml:"avro_schema_registry"`
+	AvroSchemaFile      string   `toml:"avro_schema_file"`
+	AvroMeasurement     string   `toml:"avro_measurement"`
+	AvroTags            []string `toml:"avro_tags"`
+	AvroFields          []string `toml:"avro_fields"`
+	AvroFieldSeparator  string   `toml:"avro_field_separator"`
+	AvroTimestamp       string   `toml:"avro_timestamp"`
+	AvroTimestampFormat string   `toml:"avro_timestamp_format"`
 }
 
 // NewParser returns a Parser interface based on the given config.
@@ -174,7 +189,7 @@ func NewParser(config *Config) (Parser, error) {
 		parser, err = NewValueParser(config.MetricName,
 			config.DataType, config.DefaultTags)
 	case "influx":
-		parser, err = NewInfluxParser()
+		parser, err = newInfluxParserWithSchema(config.InfluxSchemaFile, config.InfluxSchemaAction)
 	case "nagios":
 		parser, err = NewNagiosParser()
 	case "graphite":
@@ -232,6 +247,21 @@ func NewParser(config *Config) (Parser, error) {
 			config.DefaultTags,
 			config.FormUrlencodedTagKeys,
 		)
+	case "avro":
+		parser, err = avro.New(
+			&avro.Config{
+				MetricName:      config.MetricName,
+				SchemaRegistry:  config.AvroSchemaRegistry,
+				SchemaFile:      config.AvroSchemaFile,
+				Measurement:     config.AvroMeasurement,
+				Tags:            config.AvroTags,
+				Fields:          config.AvroFields,
+				FieldSeparator:  config.AvroFieldSeparator,
+				Timestamp:       config.AvroTimestamp,
+				TimestampFormat: config.AvroTimestampFormat,
+				DefaultTags:     config.DefaultTags,
+			},
+		)
 	default:
 		err = fmt.Errorf("Invalid data format: %s", config.DataFormat)
 	}
@@ -265,6 +295,19 @@ func NewInfluxParser() (Parser, error) {
 	return influx.NewParser(handler), nil
 }
 
+func newInfluxParserWithSchema(schemaFile, schemaAction string) (Parser, error) {
+	handler := influx.NewMetricHandler()
+	parser := influx.NewParser(handler)
+	if schemaFile != "" {
+		schema, err := influx.LoadSchema(schemaFile, schemaAction)
+		if err != nil {
+			return nil, err
+		}
+		parser.SetSchema(schema)
+	}
+	return parser, nil
+}
+
 func NewGraphiteParser(
 	separator string,
 	templates []string,
@@ -4,12 +4,16 @@ import (
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/clone"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/converter"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/date"
+	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/deadband"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/dedup"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/defaults"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/enum"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/execd"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/filepath"
+	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/filter"
+	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/geoip"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/ifname"
+	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/lookup"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/override"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/parser"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/pivot"
@@ -24,5 +28,6 @@ import (
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/tag_limit"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/template"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/topk"
+	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/units"
 	_ "github.com/shanas-swi/telegraf-v1.16.3/plugins/processors/unpivot"
 )
@@ -0,0 +1,194 @@
+package deadband
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/config"
+	"github.com/shanas-swi/telegraf-v1.16.3/filter"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/processors"
+)
+
+var sampleConfig = `
+  ## Fields the deadband is applied to, may contain globs.  Other fields
+  ## are always passed through.
+  # fields = ["*"]
+
+  ## Change of a numeric field required to emit it again, as absolute value
+  ## and in percent of the last emitted value.  The field is emitted if
+  ## either of the thresholds is exceeded.  With both thresholds unset, any
+  ## change is emitted.  Non-numeric fields are emitted on any change.
+  # absolute = 0.0
+  # percent = 0.0
+
+  ## Maximum time a field is suppressed, it is emitted again after this
+  ## interval even if it did not change.  Set to 0 to suppress fields until
+  ## they change.
+  # max_interval = "10m"
+`
+
+type Deadband struct {
+	Fields      []string        `toml:"fields"`
+	Absolute    float64         `toml:"absolute"`
+	Percent     float64         `toml:"percent"`
+	MaxInterval config.Duration `toml:"max_interval"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	filter    filter.Filter
+	cache     map[uint64]map[string]*emitted
+	nextClean time.Time
+}
+
+// emitted is the last emitted value of a field.
+type emitted struct {
+	value interface{}
+	time  time.Time
+
+	// Wall clock time the value was emitted, used to clean up the cache
+	// independent of the metric timestamps.
+	stored time.Time
+}
+
+func (d *Deadband) SampleConfig() string {
+	return sampleConfig
+}
+
+func (d *Deadband) Description() string {
+	return "Suppress fields until they change by more than a threshold"
+}
+
+func (d *Deadband) Init() error {
+	if d.Absolute < 0 || d.Percent < 0 {
+		return errors.New("thresholds must not be negative")
+	}
+	if d.MaxInterval < 0 {
+		return errors.New("max_interval must not be negative")
+	}
+	if len(d.Fields) == 0 {
+		d.Fields = []string{"*"}
+	}
+
+	var err error
+	d.filter, err = filter.Compile(d.Fields)
+	if err != nil {
+		return err
+	}
+	d.cache = make(map[uint64]map[string]*emitted)
+	d.nextClean = time.Now().Add(time.Duration(d.MaxInterval))
+	return nil
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// changed returns true if the value moved out of the deadband around the
+// last emitted value.
+func (d *Deadband) changed(last, value interface{}) bool {
+	l, ok := toFloat(last)
+	if !ok {
+		return last != value
+	}
+	v, ok := toFloat(value)
+	if !ok {
+		return true
+	}
+
+	delta := math.Abs(v - l)
+	if d.Absolute == 0 && d.Percent == 0 {
+		return delta > 0
+	}
+	if d.Absolute > 0 && delta > d.Absolute {
+		return true
+	}
+	return d.Percent > 0 && delta > math.Abs(l)*d.Percent/100
+}
+
+// emit decides if the field is emitted and updates the last emitted value.
+func (d *Deadband) emit(fields map[string]*emitted, field *telegraf.Field, tm time.Time) bool {
+	last, ok := fields[field.Key]
+	if ok && !d.changed(last.value, field.Value) &&
+		(d.MaxInterval == 0 || tm.Sub(last.time) < time.Duration(d.MaxInterval)) {
+		return false
+	}
+	fields[field.Key] = &emitted{value: field.Value, time: tm, stored: time.Now()}
+	return true
+}
+
+func (d *Deadband) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	out := in[:0]
+	for _, m := range in {
+		id := m.HashID()
+		fields, ok := d.cache[id]
+		if !ok {
+			fields = make(map[string]*emitted)
+			d.cache[id] = fields
+		}
+
+		var suppressed []string
+		for _, field := range m.FieldList() {
+			if !d.filter.Match(field.Key) {
+				continue
+			}
+			if !d.emit(fields, field, m.Time()) {
+				suppressed = append(suppressed, field.Key)
+			}
+		}
+		for _, key := range suppressed {
+			m.RemoveField(key)
+		}
+
+		if len(m.FieldList()) == 0 {
+			m.Drop()
+			continue
+		}
+		out = append(out, m)
+	}
+
+	d.cleanup()
+	return out
+}
+
+// cleanup removes fields that were not emitted within the max interval, the
+// next value of these fields is emitted anyway.
+func (d *Deadband) cleanup() {
+	if d.MaxInterval == 0 {
+		return
+	}
+	now := time.Now()
+	if now.Before(d.nextClean) {
+		return
+	}
+	d.nextClean = now.Add(time.Duration(d.MaxInterval))
+
+	for id, fields := range d.cache {
+		for key, last := range fields {
+			if now.Sub(last.stored) >= time.Duration(d.MaxInterval) {
+				delete(fields, key)
+			}
+		}
+		if len(fields) == 0 {
+			delete(d.cache, id)
+		}
+	}
+}
+
+func init() {
+	processors.Add("deadband", func() telegraf.Processor {
+		return &Deadband{
+			MaxInterval: config.Duration(10 * time.Minute),
+		}
+	})
+}
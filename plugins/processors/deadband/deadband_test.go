@@ -0,0 +1,190 @@
+package deadband
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/config"
+	"github.com/shanas-swi/telegraf-v1.16.3/metric"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func sensor(fields map[string]interface{}, sec int64) telegraf.Metric {
+	return testutil.MustMetric("sensor",
+		map[string]string{"id": "1"},
+		fields,
+		time.Unix(sec, 0))
+}
+
+func TestDeadband(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugin   *Deadband
+		input    []telegraf.Metric
+		expected []telegraf.Metric
+	}{
+		{
+			name:   "any change",
+			plugin: &Deadband{},
+			input: []telegraf.Metric{
+				sensor(map[string]interface{}{"value": 1.0, "state": "ok"}, 0),
+				sensor(map[string]interface{}{"value": 1.0, "state": "ok"}, 10),
+				sensor(map[string]interface{}{"value": 1.0, "state": "fault"}, 20),
+				sensor(map[string]interface{}{"value": int64(2), "state": "fault"}, 30),
+			},
+			expected: []telegraf.Metric{
+				sensor(map[string]interface{}{"value": 1.0, "state": "ok"}, 0),
+				sensor(map[string]interface{}{"state": "fault"}, 20),
+				sensor(map[string]interface{}{"value": int64(2)}, 30),
+			},
+		},
+		{
+			name:   "absolute",
+			plugin: &Deadband{Absolute: 0.5},
+			input: []telegraf.Metric{
+				sensor(map[string]interface{}{"value": 10.0}, 0),
+				sensor(map[string]interface{}{"value": 10.4}, 10),
+				sensor(map[string]interface{}{"value": 10.5}, 20),
+				sensor(map[string]interface{}{"value": 10.6}, 30),
+				sensor(map[string]interface{}{"value": 10.2}, 40),
+				sensor(map[string]interface{}{"value": 9.9}, 50),
+			},
+			expected: []telegraf.Metric{
+				sensor(map[string]interface{}{"value": 10.0}, 0),
+				sensor(map[string]interface{}{"value": 10.6}, 30),
+				sensor(map[string]interface{}{"value": 9.9}, 50),
+			},
+		},
+		{
+			name:   "percent",
+			plugin: &Deadband{Percent: 10},
+			input: []telegraf.Metric{
+				sensor(map[string]interface{}{"value": int64(-100)}, 0),
+				sensor(map[string]interface{}{"value": int64(-109)}, 10),
+				sensor(map[string]interface{}{"value": int64(-111)}, 20),
+				sensor(map[string]interface{}{"value": uint64(0)}, 30),
+			},
+			expected: []telegraf.Metric{
+				sensor(map[string]interface{}{"value": int64(-100)}, 0),
+				sensor(map[string]interface{}{"value": int64(-111)}, 20),
+				sensor(map[string]interface{}{"value": uint64(0)}, 30),
+			},
+		},
+		{
+			name:   "absolute or percent",
+			plugin: &Deadband{Absolute: 50, Percent: 5},
+			input: []telegraf.Metric{
+				sensor(map[string]interface{}{"small": 100.0, "large": 1000.0}, 0),
+				sensor(map[string]interface{}{"small": 104.0, "large": 1040.0}, 10),
+				sensor(map[string]interface{}{"small": 106.0, "large": 1045.0}, 20),
+				sensor(map[string]interface{}{"small": 106.0, "large": 1051.0}, 30),
+			},
+			expected: []telegraf.Metric{
+				sensor(map[string]interface{}{"small": 100.0, "large": 1000.0}, 0),
+				sensor(map[string]interface{}{"small": 106.0}, 20),
+				sensor(map[string]interface{}{"large": 1051.0}, 30),
+			},
+		},
+		{
+			name:   "selected fields",
+			plugin: &Deadband{Fields: []string{"temp_*"}, Absolute: 1},
+			input: []telegraf.Metric{
+				sensor(map[string]interface{}{"temp_in": 20.0, "count": int64(1)}, 0),
+				sensor(map[string]interface{}{"temp_in": 20.5, "count": int64(1)}, 10),
+			},
+			expected: []telegraf.Metric{
+				sensor(map[string]interface{}{"temp_in": 20.0, "count": int64(1)}, 0),
+				sensor(map[string]interface{}{"count": int64(1)}, 10),
+			},
+		},
+		{
+			name:   "max interval",
+			plugin: &Deadband{Absolute: 1, MaxInterval: config.Duration(time.Minute)},
+			input: []telegraf.Metric{
+				sensor(map[string]interface{}{"value": 1.0}, 0),
+				sensor(map[string]interface{}{"value": 1.0}, 30),
+				sensor(map[string]interface{}{"value": 1.5}, 60),
+				sensor(map[string]interface{}{"value": 1.0}, 90),
+				sensor(map[string]interface{}{"value": 3.0}, 100),
+				sensor(map[string]interface{}{"value": 3.0}, 159),
+				sensor(map[string]interface{}{"value": 3.0}, 160),
+			},
+			expected: []telegraf.Metric{
+				sensor(map[string]interface{}{"value": 1.0}, 0),
+				sensor(map[string]interface{}{"value": 1.5}, 60),
+				sensor(map[string]interface{}{"value": 3.0}, 100),
+				sensor(map[string]interface{}{"value": 3.0}, 160),
+			},
+		},
+		{
+			name:   "series",
+			plugin: &Deadband{Absolute: 1},
+			input: []telegraf.Metric{
+				sensor(map[string]interface{}{"value": 1.0}, 0),
+				testutil.MustMetric("sensor",
+					map[string]string{"id": "2"},
+					map[string]interface{}{"value": 1.0},
+					time.Unix(0, 0)),
+				sensor(map[string]interface{}{"value": 1.0}, 10),
+			},
+			expected: []telegraf.Metric{
+				sensor(map[string]interface{}{"value": 1.0}, 0),
+				testutil.MustMetric("sensor",
+					map[string]string{"id": "2"},
+					map[string]interface{}{"value": 1.0},
+					time.Unix(0, 0)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.plugin.Log = testutil.Logger{}
+			require.NoError(t, tt.plugin.Init())
+
+			var actual []telegraf.Metric
+			for _, m := range tt.input {
+				actual = append(actual, tt.plugin.Apply(m)...)
+			}
+			testutil.RequireMetricsEqual(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestDropTrackingMetric(t *testing.T) {
+	plugin := &Deadband{Log: testutil.Logger{}}
+	require.NoError(t, plugin.Init())
+
+	require.Len(t, plugin.Apply(sensor(map[string]interface{}{"value": 1.0}, 0)), 1)
+
+	var delivered bool
+	m, _ := metric.WithTracking(sensor(map[string]interface{}{"value": 1.0}, 10), func(telegraf.DeliveryInfo) { delivered = true })
+	require.Empty(t, plugin.Apply(m))
+	require.True(t, delivered)
+}
+
+func TestCleanup(t *testing.T) {
+	plugin := &Deadband{
+		MaxInterval: config.Duration(time.Minute),
+		Log:         testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	plugin.Apply(sensor(map[string]interface{}{"value": 1.0}, 0))
+	require.Len(t, plugin.cache, 1)
+
+	for _, fields := range plugin.cache {
+		fields["value"].stored = time.Now().Add(-2 * time.Minute)
+	}
+	plugin.nextClean = time.Now()
+	plugin.cleanup()
+	require.Empty(t, plugin.cache)
+}
+
+func TestInitError(t *testing.T) {
+	require.Error(t, (&Deadband{Absolute: -1}).Init())
+	require.Error(t, (&Deadband{Percent: -1}).Init())
+	require.Error(t, (&Deadband{MaxInterval: -1}).Init())
+}
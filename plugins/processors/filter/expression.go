@@ -0,0 +1,506 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gobwas/glob"
+	"github.com/shanas-swi/telegraf-v1.16.3"
+)
+
+// expression is a compiled condition evaluated against a metric.
+type expression interface {
+	eval(m telegraf.Metric) interface{}
+}
+
+// compile parses the condition into an expression.
+func compile(condition string) (expression, error) {
+	tokens, err := tokenize(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", t, t.pos)
+	}
+	return expr, nil
+}
+
+// matches evaluates the expression in a boolean context.
+func matches(expr expression, m telegraf.Metric) bool {
+	return truth(expr.eval(m))
+}
+
+// truth returns false for missing values and false booleans, all other
+// values are true.
+func truth(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	default:
+		return true
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of condition"
+	case tokenString:
+		return strconv.Quote(t.value)
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "=~", "!~", "<", ">"}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isIdentPart(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: i})
+			i++
+		case r == '"' || r == '\'':
+			var sb strings.Builder
+			start := i
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenString, value: sb.String(), pos: start})
+		case unicode.IsDigit(r) || ((r == '-' || r == '.') && i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.')):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || strings.ContainsRune(".eE", runes[i]) ||
+				((runes[i] == '-' || runes[i] == '+') && (runes[i-1] == 'e' || runes[i-1] == 'E'))) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i]), pos: start})
+		case isIdentStart(r):
+			start := i
+			for i < len(runes) && isIdentPart(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: string(runes[start:i]), pos: start})
+		default:
+			found := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, token{kind: tokenOperator, value: op, pos: i})
+					i += len(op)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) keyword(name string) bool {
+	t := p.peek()
+	if t.kind == tokenIdent && t.value == name {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &or{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expression, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &and{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expression, error) {
+	if p.keyword("not") {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &not{expr: expr}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expression, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return left, nil
+	}
+	p.next()
+
+	if t.value == "=~" || t.value == "!~" {
+		pattern := p.next()
+		if pattern.kind != tokenString {
+			return nil, fmt.Errorf("expected pattern string after %q at position %d", t.value, pattern.pos)
+		}
+		g, err := glob.Compile(pattern.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern at position %d: %v", pattern.pos, err)
+		}
+		return &match{expr: left, glob: g, negate: t.value == "!~"}, nil
+	}
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return &comparison{op: t.value, left: left, right: right}, nil
+}
+
+func (p *parser) parseOperand() (expression, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected \")\" at position %d", closing.pos)
+		}
+		return expr, nil
+	case tokenString:
+		return &literal{value: t.value}, nil
+	case tokenNumber:
+		v, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", t.value, t.pos)
+		}
+		return &literal{value: v}, nil
+	case tokenIdent:
+		switch t.value {
+		case "true":
+			return &literal{value: true}, nil
+		case "false":
+			return &literal{value: false}, nil
+		case "and", "or", "not":
+			return nil, fmt.Errorf("unexpected %s at position %d", t, t.pos)
+		case "name":
+			return &reference{kind: referenceName}, nil
+		case "time":
+			return &reference{kind: referenceTime}, nil
+		}
+		switch {
+		case strings.HasPrefix(t.value, "tags."):
+			return &reference{kind: referenceTag, key: strings.TrimPrefix(t.value, "tags.")}, nil
+		case strings.HasPrefix(t.value, "fields."):
+			return &reference{kind: referenceField, key: strings.TrimPrefix(t.value, "fields.")}, nil
+		default:
+			return &reference{kind: referenceAny, key: t.value}, nil
+		}
+	default:
+		return nil, fmt.Errorf("unexpected %s at position %d", t, t.pos)
+	}
+}
+
+type literal struct {
+	value interface{}
+}
+
+func (l *literal) eval(telegraf.Metric) interface{} {
+	return l.value
+}
+
+type referenceKind int
+
+const (
+	referenceName referenceKind = iota
+	referenceTime
+	referenceTag
+	referenceField
+	// referenceAny refers to the field with the key, or the tag if there is
+	// no such field.
+	referenceAny
+)
+
+type reference struct {
+	kind referenceKind
+	key  string
+}
+
+func (r *reference) eval(m telegraf.Metric) interface{} {
+	switch r.kind {
+	case referenceName:
+		return m.Name()
+	case referenceTime:
+		return m.Time()
+	}
+
+	if r.kind != referenceTag {
+		if v, ok := m.GetField(r.key); ok {
+			return v
+		}
+	}
+	if r.kind != referenceField {
+		if v, ok := m.GetTag(r.key); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+type and struct {
+	left, right expression
+}
+
+func (e *and) eval(m telegraf.Metric) interface{} {
+	return truth(e.left.eval(m)) && truth(e.right.eval(m))
+}
+
+type or struct {
+	left, right expression
+}
+
+func (e *or) eval(m telegraf.Metric) interface{} {
+	return truth(e.left.eval(m)) || truth(e.right.eval(m))
+}
+
+type not struct {
+	expr expression
+}
+
+func (e *not) eval(m telegraf.Metric) interface{} {
+	return !truth(e.expr.eval(m))
+}
+
+type match struct {
+	expr   expression
+	glob   glob.Glob
+	negate bool
+}
+
+func (e *match) eval(m telegraf.Metric) interface{} {
+	s, ok := e.expr.eval(m).(string)
+	if !ok {
+		return false
+	}
+	return e.glob.Match(s) != e.negate
+}
+
+type comparison struct {
+	op          string
+	left, right expression
+}
+
+// eval compares the values, comparisons with missing values are false.
+// Values of different types are not equal and not ordered.
+func (e *comparison) eval(m telegraf.Metric) interface{} {
+	left := e.left.eval(m)
+	right := e.right.eval(m)
+	if left == nil || right == nil {
+		return false
+	}
+
+	c, ok := compare(left, right)
+	if !ok {
+		return e.op == "!="
+	}
+	if _, ok := left.(bool); ok && e.op != "==" && e.op != "!=" {
+		return false
+	}
+
+	switch e.op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// compare returns the order of the values and whether they are comparable.
+// Booleans are only compared for equality.
+func compare(left, right interface{}) (int, bool) {
+	if t, ok := left.(time.Time); ok {
+		other, ok := toTime(right)
+		if !ok {
+			return 0, false
+		}
+		return compareTime(t, other), true
+	}
+	if t, ok := right.(time.Time); ok {
+		other, ok := toTime(left)
+		if !ok {
+			return 0, false
+		}
+		return compareTime(other, t), true
+	}
+
+	if l, ok := toFloat(left); ok {
+		r, ok := toFloat(right)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case l < r:
+			return -1, true
+		case l > r:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	switch l := left.(type) {
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(l, r), true
+	case bool:
+		r, ok := right.(bool)
+		if !ok || l == r {
+			return 0, ok
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+func compareTime(l, r time.Time) int {
+	switch {
+	case l.Before(r):
+		return -1
+	case l.After(r):
+		return 1
+	}
+	return 0
+}
+
+// toTime converts RFC3339 strings and numbers of seconds since the epoch.
+func toTime(v interface{}) (time.Time, bool) {
+	if s, ok := v.(string); ok {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		return t, err == nil
+	}
+	f, ok := toFloat(v)
+	if !ok {
+		return time.Time{}, false
+	}
+	sec := int64(f)
+	return time.Unix(sec, int64((f-float64(sec))*1e9)), true
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,122 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpression(t *testing.T) {
+	m := testutil.MustMetric("cpu",
+		map[string]string{
+			"host":  "web-01",
+			"cpu":   "cpu-total",
+			"state": "tag",
+			"name":  "tag name",
+		},
+		map[string]interface{}{
+			"usage_idle":   99.5,
+			"usage_user":   int64(0),
+			"count":        uint64(42),
+			"state":        "field",
+			"ok":           true,
+			"failed":       false,
+			"name":         "field name",
+			"usage-system": 0.5,
+		},
+		time.Date(2020, 11, 30, 14, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		condition string
+		expected  bool
+	}{
+		{`name == "cpu"`, true},
+		{`name != "cpu"`, false},
+		{`usage_idle > 99`, true},
+		{`usage_idle >= 99.5`, true},
+		{`usage_idle < 99.5`, false},
+		{`usage_user <= 0`, true},
+		{`count == 42`, true},
+		{`count > 4.2e1`, false},
+		{`usage_user > -1`, true},
+		{`host == 'web-01'`, true},
+		{`host =~ "web-*"`, true},
+		{`host !~ "web-*"`, false},
+		{`host =~ "db-*"`, false},
+		{`cpu =~ "cpu[0-9]*"`, false},
+		{`usage_idle > 99 and host =~ "web-*"`, true},
+		{`usage_idle > 99 and host =~ "db-*"`, false},
+		{`usage_idle < 1 or host =~ "web-*"`, true},
+		{`not usage_idle > 99`, false},
+		{`not (usage_idle > 99 and usage_user > 1)`, true},
+		{`usage_idle > 99 or usage_user > 1 and false`, true},
+		{`(usage_idle > 99 or usage_user > 1) and false`, false},
+		// Fields take precedence over tags
+		{`state == "field"`, true},
+		{`tags.state == "tag"`, true},
+		{`fields.state == "field"`, true},
+		{`tags.name == "tag name"`, true},
+		{`fields.name == "field name"`, true},
+		{`fields.host == "web-01"`, false},
+		{`usage-system < 1`, true},
+		// Existence and booleans
+		{`ok`, true},
+		{`failed`, false},
+		{`missing`, false},
+		{`not missing`, true},
+		{`host`, true},
+		{`ok == true`, true},
+		{`failed != true`, true},
+		{`ok > false`, false},
+		// Missing values and mismatched types
+		{`missing == 0`, false},
+		{`missing != 0`, false},
+		{`host == 1`, false},
+		{`host != 1`, true},
+		{`host > 1`, false},
+		{`usage_idle =~ "99*"`, false},
+		// Time
+		{`time == "2020-11-30T14:00:00Z"`, true},
+		{`time > "2020-11-30T13:00:00Z"`, true},
+		{`time < 1606744800.5`, true},
+		{`"2020-11-30T15:00:00+01:00" == time`, true},
+		{`time == "today"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			expr, err := compile(tt.condition)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, matches(expr, m))
+		})
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	tests := []struct {
+		condition string
+		err       string
+	}{
+		{``, `unexpected end of condition at position 0`},
+		{`usage_idle >`, `unexpected end of condition at position 12`},
+		{`usage_idle > 99 and`, `unexpected end of condition at position 19`},
+		{`host == "web`, `unterminated string at position 8`},
+		{`(usage_idle > 99`, `expected ")" at position 16`},
+		{`usage_idle > 99)`, `unexpected ")" at position 15`},
+		{`host =~ web`, `expected pattern string after "=~" at position 8`},
+		{`host =~ "[web"`, `invalid pattern at position 8: unexpected end of input`},
+		{`usage_idle = 99`, `unexpected character '=' at position 11`},
+		{`usage_idle > 99 usage_user`, `unexpected "usage_user" at position 16`},
+		{`and == 1`, `unexpected "and" at position 0`},
+		{`x > 1.2.3`, `invalid number "1.2.3" at position 4`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			_, err := compile(tt.condition)
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
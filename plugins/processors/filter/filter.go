@@ -0,0 +1,104 @@
+package filter
+
+import (
+	"fmt"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/processors"
+)
+
+var sampleConfig = `
+  ## Action applied to metrics not matching any rule, "pass" or "drop".
+  # default = "pass"
+
+  ## Rules are evaluated in order, the action of the first rule with a
+  ## matching condition is applied to the metric.
+  [[processors.filter.rule]]
+    ## Condition the metric must match, see the README for the syntax.
+    condition = 'name == "cpu" and usage_idle > 99 and host =~ "web-*"'
+
+    ## Action applied to matching metrics, "pass" or "drop".
+    action = "drop"
+`
+
+const (
+	actionPass = "pass"
+	actionDrop = "drop"
+)
+
+type Rule struct {
+	Condition string `toml:"condition"`
+	Action    string `toml:"action"`
+
+	expr expression
+}
+
+type Filter struct {
+	Default string  `toml:"default"`
+	Rules   []*Rule `toml:"rule"`
+
+	Log telegraf.Logger `toml:"-"`
+}
+
+func (f *Filter) SampleConfig() string {
+	return sampleConfig
+}
+
+func (f *Filter) Description() string {
+	return "Pass or drop metrics matching conditions on the name, tags, fields and time"
+}
+
+func (f *Filter) Init() error {
+	switch f.Default {
+	case "":
+		f.Default = actionPass
+	case actionPass, actionDrop:
+	default:
+		return fmt.Errorf("invalid default action %q", f.Default)
+	}
+
+	for i, rule := range f.Rules {
+		switch rule.Action {
+		case actionPass, actionDrop:
+		default:
+			return fmt.Errorf("rule %d: invalid action %q", i+1, rule.Action)
+		}
+
+		if rule.Condition == "" {
+			return fmt.Errorf("rule %d: no condition specified", i+1)
+		}
+		var err error
+		rule.expr, err = compile(rule.Condition)
+		if err != nil {
+			return fmt.Errorf("rule %d: invalid condition: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (f *Filter) action(m telegraf.Metric) string {
+	for _, rule := range f.Rules {
+		if matches(rule.expr, m) {
+			return rule.Action
+		}
+	}
+	return f.Default
+}
+
+func (f *Filter) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	out := in[:0]
+	for _, m := range in {
+		if f.action(m) == actionDrop {
+			m.Drop()
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func init() {
+	processors.Add("filter", func() telegraf.Processor {
+		return &Filter{}
+	})
+}
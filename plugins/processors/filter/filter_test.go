@@ -0,0 +1,142 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/metric"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func cpu(host string, idle float64) telegraf.Metric {
+	return testutil.MustMetric("cpu",
+		map[string]string{"host": host},
+		map[string]interface{}{"usage_idle": idle},
+		time.Unix(0, 0))
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugin   *Filter
+		input    []telegraf.Metric
+		expected []telegraf.Metric
+	}{
+		{
+			name: "drop matching",
+			plugin: &Filter{
+				Rules: []*Rule{
+					{Condition: `usage_idle > 99 and host =~ "web-*"`, Action: "drop"},
+				},
+			},
+			input: []telegraf.Metric{
+				cpu("web-01", 99.5),
+				cpu("web-02", 50),
+				cpu("db-01", 99.5),
+			},
+			expected: []telegraf.Metric{
+				cpu("web-02", 50),
+				cpu("db-01", 99.5),
+			},
+		},
+		{
+			name: "first matching rule",
+			plugin: &Filter{
+				Default: "drop",
+				Rules: []*Rule{
+					{Condition: `host == "web-01"`, Action: "pass"},
+					{Condition: `usage_idle > 99`, Action: "drop"},
+					{Condition: `host =~ "web-*"`, Action: "pass"},
+				},
+			},
+			input: []telegraf.Metric{
+				cpu("web-01", 99.5),
+				cpu("web-02", 99.5),
+				cpu("web-03", 50),
+				cpu("db-01", 50),
+			},
+			expected: []telegraf.Metric{
+				cpu("web-01", 99.5),
+				cpu("web-03", 50),
+			},
+		},
+		{
+			name:   "no rules",
+			plugin: &Filter{},
+			input: []telegraf.Metric{
+				cpu("web-01", 99.5),
+			},
+			expected: []telegraf.Metric{
+				cpu("web-01", 99.5),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.plugin.Log = testutil.Logger{}
+			require.NoError(t, tt.plugin.Init())
+			actual := tt.plugin.Apply(tt.input...)
+			testutil.RequireMetricsEqual(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestDropTrackingMetric(t *testing.T) {
+	plugin := &Filter{
+		Default: "drop",
+		Log:     testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	var delivered bool
+	m, _ := metric.WithTracking(cpu("web-01", 99.5), func(telegraf.DeliveryInfo) { delivered = true })
+	require.Empty(t, plugin.Apply(m))
+	require.True(t, delivered)
+}
+
+func TestInitError(t *testing.T) {
+	tests := []struct {
+		name   string
+		plugin *Filter
+		err    string
+	}{
+		{
+			name:   "invalid default",
+			plugin: &Filter{Default: "accept"},
+			err:    `invalid default action "accept"`,
+		},
+		{
+			name: "invalid action",
+			plugin: &Filter{
+				Rules: []*Rule{{Condition: "true", Action: "reject"}},
+			},
+			err: `rule 1: invalid action "reject"`,
+		},
+		{
+			name: "missing condition",
+			plugin: &Filter{
+				Rules: []*Rule{{Action: "drop"}},
+			},
+			err: "rule 1: no condition specified",
+		},
+		{
+			name: "invalid condition",
+			plugin: &Filter{
+				Rules: []*Rule{
+					{Condition: "true", Action: "pass"},
+					{Condition: "usage_idle >", Action: "drop"},
+				},
+			},
+			err: "rule 2: invalid condition: unexpected end of condition at position 12",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.EqualError(t, tt.plugin.Init(), tt.err)
+		})
+	}
+}
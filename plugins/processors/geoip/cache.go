@@ -0,0 +1,55 @@
+package geoip
+
+import (
+	"container/list"
+)
+
+// lruCache keeps the results of the most recently looked up addresses.
+type lruCache struct {
+	capacity int
+	l        *list.List
+	m        map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key   string
+	value *record
+}
+
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{
+		capacity: capacity,
+		l:        list.New(),
+		m:        make(map[string]*list.Element, capacity),
+	}
+}
+
+func (c *lruCache) get(key string) (*record, bool) {
+	if e, ok := c.m[key]; ok {
+		c.l.MoveToFront(e)
+		return e.Value.(*cacheEntry).value, true
+	}
+	return nil, false
+}
+
+func (c *lruCache) put(key string, value *record) {
+	if c.capacity <= 0 {
+		return
+	}
+	if e, ok := c.m[key]; ok {
+		c.l.MoveToFront(e)
+		e.Value.(*cacheEntry).value = value
+		return
+	}
+	if c.l.Len() >= c.capacity {
+		oldest := c.l.Back()
+		delete(c.m, oldest.Value.(*cacheEntry).key)
+		c.l.Remove(oldest)
+	}
+	c.m[key] = c.l.PushFront(&cacheEntry{key: key, value: value})
+}
+
+func (c *lruCache) clear() {
+	c.l.Init()
+	c.m = make(map[string]*list.Element, c.capacity)
+}
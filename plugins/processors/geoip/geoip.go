@@ -0,0 +1,319 @@
+package geoip
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/config"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/processors"
+)
+
+var sampleConfig = `
+  ## MaxMind database files in the MMDB format, for example the GeoLite2
+  ## City, Country and ASN databases.  The results of all databases are
+  ## combined.
+  databases = [
+    "/var/lib/GeoIP/GeoLite2-City.mmdb",
+    "/var/lib/GeoIP/GeoLite2-ASN.mmdb",
+  ]
+
+  ## Tags containing the IP addresses to look up, the results are added as
+  ## tags named after the source tag and the property, e.g. "src_ip_city".
+  tags = ["src_ip"]
+
+  ## Fields containing the IP addresses to look up, the results are added as
+  ## fields.
+  # fields = []
+
+  ## Properties to add, available are "continent_code", "country_code",
+  ## "country_name", "city", "latitude", "longitude", "asn" and "as_org".
+  ## Latitude and longitude are always added as fields.
+  # properties = ["country_code", "city", "latitude", "longitude", "asn", "as_org"]
+
+  ## Language of the country and city names.
+  # language = "en"
+
+  ## Maximum number of addresses to cache the results of.
+  # cache_size = 1000
+
+  ## Interval to check the databases for modifications, changed databases
+  ## are reloaded.  Set to 0 to disable reloading.
+  # reload_interval = "1h"
+`
+
+var properties = map[string]bool{
+	"continent_code": true,
+	"country_code":   true,
+	"country_name":   true,
+	"city":           true,
+	"latitude":       true,
+	"longitude":      true,
+	"asn":            true,
+	"as_org":         true,
+}
+
+// record holds the supported properties of the city, country and ASN
+// databases.
+type record struct {
+	Continent struct {
+		Code string `maxminddb:"code"`
+	} `maxminddb:"continent"`
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Location struct {
+		Latitude  *float64 `maxminddb:"latitude"`
+		Longitude *float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+	ASN   uint64 `maxminddb:"autonomous_system_number"`
+	ASOrg string `maxminddb:"autonomous_system_organization"`
+}
+
+type database struct {
+	filename string
+	modTime  time.Time
+	reader   *maxminddb.Reader
+}
+
+type GeoIP struct {
+	Databases      []string        `toml:"databases"`
+	Tags           []string        `toml:"tags"`
+	Fields         []string        `toml:"fields"`
+	Properties     []string        `toml:"properties"`
+	Language       string          `toml:"language"`
+	CacheSize      int             `toml:"cache_size"`
+	ReloadInterval config.Duration `toml:"reload_interval"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	databases []*database
+	cache     *lruCache
+	nextCheck time.Time
+}
+
+func (g *GeoIP) SampleConfig() string {
+	return sampleConfig
+}
+
+func (g *GeoIP) Description() string {
+	return "Add geolocation and ASN information of IP addresses from MaxMind databases"
+}
+
+func (g *GeoIP) Init() error {
+	if len(g.Databases) == 0 {
+		return errors.New("no databases specified")
+	}
+	if len(g.Tags) == 0 && len(g.Fields) == 0 {
+		return errors.New("no tags or fields specified")
+	}
+	if len(g.Properties) == 0 {
+		g.Properties = []string{"country_code", "city", "latitude", "longitude", "asn", "as_org"}
+	}
+	for _, property := range g.Properties {
+		if !properties[property] {
+			return fmt.Errorf("invalid property %q", property)
+		}
+	}
+	if g.Language == "" {
+		g.Language = "en"
+	}
+
+	g.cache = newLRUCache(g.CacheSize)
+	for _, filename := range g.Databases {
+		db, err := openDatabase(filename)
+		if err != nil {
+			g.close()
+			return err
+		}
+		g.databases = append(g.databases, db)
+	}
+	g.nextCheck = time.Now().Add(time.Duration(g.ReloadInterval))
+	return nil
+}
+
+// close closes the databases.
+func (g *GeoIP) close() {
+	for _, db := range g.databases {
+		db.reader.Close()
+	}
+	g.databases = nil
+}
+
+func openDatabase(filename string) (*database, error) {
+	stat, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := maxminddb.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("opening %q failed: %v", filename, err)
+	}
+	return &database{filename: filename, modTime: stat.ModTime(), reader: reader}, nil
+}
+
+// reload opens the databases again that were modified.  On errors the
+// previous database is kept.
+func (g *GeoIP) reload() {
+	now := time.Now()
+	if g.ReloadInterval <= 0 || now.Before(g.nextCheck) {
+		return
+	}
+	g.nextCheck = now.Add(time.Duration(g.ReloadInterval))
+
+	for i, db := range g.databases {
+		stat, err := os.Stat(db.filename)
+		if err != nil {
+			g.Log.Errorf("Checking %q failed: %v", db.filename, err)
+			continue
+		}
+		if stat.ModTime().Equal(db.modTime) {
+			continue
+		}
+
+		reloaded, err := openDatabase(db.filename)
+		if err != nil {
+			g.Log.Errorf("Reloading failed, keeping the previous database: %v", err)
+			continue
+		}
+		db.reader.Close()
+		g.databases[i] = reloaded
+		g.cache.clear()
+		g.Log.Debugf("Reloaded %q", db.filename)
+	}
+}
+
+func (g *GeoIP) lookup(address string) (*record, bool) {
+	if r, ok := g.cache.get(address); ok {
+		return r, r != nil
+	}
+
+	ip := net.ParseIP(address)
+	if ip == nil {
+		g.Log.Debugf("Invalid IP address %q", address)
+		return nil, false
+	}
+
+	r := &record{}
+	found := false
+	for _, db := range g.databases {
+		// The results of all databases are decoded into the same record.
+		_, ok, err := db.reader.LookupNetwork(ip, r)
+		if err != nil {
+			g.Log.Errorf("Looking up %q in %q failed: %v", address, db.filename, err)
+			continue
+		}
+		found = found || ok
+	}
+	if !found {
+		r = nil
+	}
+	g.cache.put(address, r)
+	return r, found
+}
+
+// values returns the values of the configured properties of the record.
+func (g *GeoIP) values(r *record) map[string]interface{} {
+	values := make(map[string]interface{}, len(g.Properties))
+	for _, property := range g.Properties {
+		switch property {
+		case "continent_code":
+			if r.Continent.Code != "" {
+				values[property] = r.Continent.Code
+			}
+		case "country_code":
+			if r.Country.ISOCode != "" {
+				values[property] = r.Country.ISOCode
+			}
+		case "country_name":
+			if name := r.Country.Names[g.Language]; name != "" {
+				values[property] = name
+			}
+		case "city":
+			if name := r.City.Names[g.Language]; name != "" {
+				values[property] = name
+			}
+		case "latitude":
+			if r.Location.Latitude != nil {
+				values[property] = *r.Location.Latitude
+			}
+		case "longitude":
+			if r.Location.Longitude != nil {
+				values[property] = *r.Location.Longitude
+			}
+		case "asn":
+			if r.ASN != 0 {
+				values[property] = r.ASN
+			}
+		case "as_org":
+			if r.ASOrg != "" {
+				values[property] = r.ASOrg
+			}
+		}
+	}
+	return values
+}
+
+func (g *GeoIP) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	g.reload()
+
+	for _, m := range in {
+		for _, tag := range g.Tags {
+			address, ok := m.GetTag(tag)
+			if !ok {
+				continue
+			}
+			r, ok := g.lookup(address)
+			if !ok {
+				continue
+			}
+			for property, value := range g.values(r) {
+				key := tag + "_" + property
+				switch v := value.(type) {
+				case float64:
+					m.AddField(key, v)
+				case uint64:
+					m.AddTag(key, fmt.Sprint(v))
+				case string:
+					m.AddTag(key, v)
+				}
+			}
+		}
+
+		for _, field := range g.Fields {
+			value, ok := m.GetField(field)
+			if !ok {
+				continue
+			}
+			address, ok := value.(string)
+			if !ok {
+				continue
+			}
+			r, ok := g.lookup(address)
+			if !ok {
+				continue
+			}
+			for property, value := range g.values(r) {
+				m.AddField(field+"_"+property, value)
+			}
+		}
+	}
+	return in
+}
+
+func init() {
+	processors.Add("geoip", func() telegraf.Processor {
+		return &GeoIP{
+			CacheSize:      1000,
+			ReloadInterval: config.Duration(time.Hour),
+		}
+	})
+}
@@ -0,0 +1,226 @@
+package geoip
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/config"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// The test databases contain made up entries for 81.2.69.0/24,
+// 89.160.20.0/24 and 2a02:cf40::/29 (city only).
+var testDatabases = []string{
+	"testdata/GeoIP2-City-Test.mmdb",
+	"testdata/GeoLite2-ASN-Test.mmdb",
+}
+
+func TestTags(t *testing.T) {
+	plugin := &GeoIP{
+		Databases: testDatabases,
+		Tags:      []string{"src", "dst"},
+		CacheSize: 10,
+		Log:       testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	input := []telegraf.Metric{
+		testutil.MustMetric("flow",
+			map[string]string{"src": "81.2.69.142", "dst": "2a02:cf40::1"},
+			map[string]interface{}{"bytes": 42},
+			time.Unix(0, 0)),
+		testutil.MustMetric("flow",
+			map[string]string{"src": "10.0.0.1", "dst": "invalid"},
+			map[string]interface{}{"bytes": 42},
+			time.Unix(0, 0)),
+	}
+	expected := []telegraf.Metric{
+		testutil.MustMetric("flow",
+			map[string]string{
+				"src":              "81.2.69.142",
+				"src_country_code": "GB",
+				"src_city":         "London",
+				"src_asn":          "20712",
+				"src_as_org":       "Andrews & Arnold Ltd",
+				"dst":              "2a02:cf40::1",
+				"dst_country_code": "SE",
+			},
+			map[string]interface{}{
+				"bytes":         42,
+				"src_latitude":  51.5142,
+				"src_longitude": -0.0931,
+				"dst_latitude":  62.0,
+				"dst_longitude": 15.0,
+			},
+			time.Unix(0, 0)),
+		testutil.MustMetric("flow",
+			map[string]string{"src": "10.0.0.1", "dst": "invalid"},
+			map[string]interface{}{"bytes": 42},
+			time.Unix(0, 0)),
+	}
+
+	actual := plugin.Apply(input...)
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestFields(t *testing.T) {
+	plugin := &GeoIP{
+		Databases:  testDatabases,
+		Fields:     []string{"client"},
+		Properties: []string{"continent_code", "country_name", "asn"},
+		CacheSize:  10,
+		Log:        testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	input := []telegraf.Metric{
+		testutil.MustMetric("nginx",
+			map[string]string{},
+			map[string]interface{}{"client": "89.160.20.128"},
+			time.Unix(0, 0)),
+		testutil.MustMetric("nginx",
+			map[string]string{},
+			map[string]interface{}{"client": 42},
+			time.Unix(0, 0)),
+	}
+	expected := []telegraf.Metric{
+		testutil.MustMetric("nginx",
+			map[string]string{},
+			map[string]interface{}{
+				"client":                "89.160.20.128",
+				"client_continent_code": "EU",
+				"client_country_name":   "Sweden",
+				"client_asn":            uint64(29518),
+			},
+			time.Unix(0, 0)),
+		testutil.MustMetric("nginx",
+			map[string]string{},
+			map[string]interface{}{"client": 42},
+			time.Unix(0, 0)),
+	}
+
+	actual := plugin.Apply(input...)
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestCache(t *testing.T) {
+	c := newLRUCache(2)
+	c.put("a", &record{ASN: 1})
+	c.put("b", nil)
+	_, ok := c.get("a")
+	require.True(t, ok)
+
+	// b is the least recently used entry
+	c.put("c", &record{ASN: 3})
+	_, ok = c.get("b")
+	require.False(t, ok)
+	r, ok := c.get("a")
+	require.True(t, ok)
+	require.Equal(t, uint64(1), r.ASN)
+
+	c.clear()
+	_, ok = c.get("a")
+	require.False(t, ok)
+}
+
+func TestReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "geoip")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	copyDatabase := func(src string, modTime time.Time) string {
+		buf, err := ioutil.ReadFile(src)
+		require.NoError(t, err)
+		filename := filepath.Join(dir, "GeoIP.mmdb")
+		require.NoError(t, ioutil.WriteFile(filename, buf, 0644))
+		require.NoError(t, os.Chtimes(filename, modTime, modTime))
+		return filename
+	}
+	filename := copyDatabase("testdata/GeoLite2-ASN-Test.mmdb", time.Unix(1000, 0))
+
+	plugin := &GeoIP{
+		Databases:      []string{filename},
+		Tags:           []string{"src"},
+		Properties:     []string{"asn", "country_code"},
+		CacheSize:      10,
+		ReloadInterval: config.Duration(time.Nanosecond),
+		Log:            testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	m := testutil.MustMetric("flow",
+		map[string]string{"src": "81.2.69.142"},
+		map[string]interface{}{"bytes": 42},
+		time.Unix(0, 0))
+
+	actual := plugin.Apply(m.Copy())
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{
+		testutil.MustMetric("flow",
+			map[string]string{"src": "81.2.69.142", "src_asn": "20712"},
+			map[string]interface{}{"bytes": 42},
+			time.Unix(0, 0)),
+	}, actual)
+
+	copyDatabase("testdata/GeoIP2-City-Test.mmdb", time.Unix(2000, 0))
+	actual = plugin.Apply(m.Copy())
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{
+		testutil.MustMetric("flow",
+			map[string]string{"src": "81.2.69.142", "src_country_code": "GB"},
+			map[string]interface{}{"bytes": 42},
+			time.Unix(0, 0)),
+	}, actual)
+
+	// An invalid database keeps the previous one.
+	require.NoError(t, ioutil.WriteFile(filename, []byte("invalid"), 0644))
+	require.NoError(t, os.Chtimes(filename, time.Unix(3000, 0), time.Unix(3000, 0)))
+	actual = plugin.Apply(m.Copy())
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{
+		testutil.MustMetric("flow",
+			map[string]string{"src": "81.2.69.142", "src_country_code": "GB"},
+			map[string]interface{}{"bytes": 42},
+			time.Unix(0, 0)),
+	}, actual)
+}
+
+func TestInitError(t *testing.T) {
+	tests := []struct {
+		name   string
+		plugin *GeoIP
+	}{
+		{
+			name:   "no databases",
+			plugin: &GeoIP{Tags: []string{"src"}},
+		},
+		{
+			name:   "no sources",
+			plugin: &GeoIP{Databases: testDatabases},
+		},
+		{
+			name: "invalid property",
+			plugin: &GeoIP{
+				Databases:  testDatabases,
+				Tags:       []string{"src"},
+				Properties: []string{"timezone"},
+			},
+		},
+		{
+			name: "missing database",
+			plugin: &GeoIP{
+				Databases: []string{"testdata/missing.mmdb"},
+				Tags:      []string{"src"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.plugin.Log = testutil.Logger{}
+			require.Error(t, tt.plugin.Init())
+		})
+	}
+}
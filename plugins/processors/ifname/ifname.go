@@ -25,6 +25,10 @@ var sampleConfig = `
   ## Name of tag of the SNMP agent to request the interface name from
   # agent = "agent"
 
+  ## Paths to the directories containing MIB files.  The IF-MIB module is
+  ## needed to look up the interface tables.
+  # path = ["/usr/share/snmp/mibs"]
+
   ## Timeout for each request.
   # timeout = "5s"
 
@@ -125,6 +129,10 @@ func (d *IfName) Description() string {
 }
 
 func (d *IfName) Init() error {
+	if err := snmp.LoadMibsFromPath(d.Path, d.Log); err != nil {
+		return err
+	}
+
 	d.getMapRemote = d.getMapRemoteNoMock
 	d.makeTable = makeTableNoMock
 
@@ -341,6 +349,7 @@ func init() {
 				Timeout:        internal.Duration{Duration: 5 * time.Second},
 				Version:        2,
 				Community:      "public",
+				Path:           []string{"/usr/share/snmp/mibs"},
 			},
 			CacheTTL: config.Duration(8 * time.Hour),
 		}
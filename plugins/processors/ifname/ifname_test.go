@@ -19,7 +19,12 @@ func TestTable(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	d := IfName{}
+	d := IfName{
+		ClientConfig: snmp.ClientConfig{
+			Path: []string{"../../inputs/snmp/testdata/mibs"},
+		},
+		Log: testutil.Logger{},
+	}
 	d.Init()
 	tab, err := d.makeTable("IF-MIB::ifTable")
 	require.NoError(t, err)
@@ -54,7 +59,9 @@ func TestIfName(t *testing.T) {
 		ClientConfig: snmp.ClientConfig{
 			Version: 2,
 			Timeout: internal.Duration{Duration: 5 * time.Second}, // Doesn't work with 0 timeout
+			Path:    []string{"../../inputs/snmp/testdata/mibs"},
 		},
+		Log: testutil.Logger{},
 	}
 	err := d.Init()
 	require.NoError(t, err)
@@ -97,7 +104,7 @@ func TestGetMap(t *testing.T) {
 		CacheTTL:  config.Duration(10 * time.Second),
 	}
 
-	// Don't run net-snmp commands to look up table names.
+	// Don't look up table names in the MIB modules.
 	d.makeTable = func(agent string) (*si.Table, error) {
 		return &si.Table{}, nil
 	}
@@ -0,0 +1,308 @@
+package lookup
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/config"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/processors"
+)
+
+var sampleConfig = `
+  ## List of files containing the lookup tables.  Entries of later files
+  ## replace entries with the same key of earlier files.
+  files = ["/etc/telegraf/inventory.csv"]
+
+  ## Format of the files, one of "csv" or "json".  CSV files must have a
+  ## header row naming the columns, JSON files must contain an array of
+  ## objects.
+  # format = "csv"
+
+  ## Tags of the metric used as key to look up the entry.
+  key_tags = ["host"]
+
+  ## Columns of the table matched against the key tags, in the same order.
+  ## By default the columns are named like the key tags.
+  # key_columns = ["hostname"]
+
+  ## Columns added as tags, by default all columns except the key columns
+  ## are added.
+  # columns = ["team", "rack", "environment"]
+
+  ## Overwrite tags already present in the metric.
+  # overwrite = false
+
+  ## Interval to check the files for modifications, changed files are
+  ## reloaded.  Set to 0 to disable reloading.
+  # reload_interval = "1m"
+`
+
+const keySeparator = "\x00"
+
+type Lookup struct {
+	Files          []string        `toml:"files"`
+	Format         string          `toml:"format"`
+	KeyTags        []string        `toml:"key_tags"`
+	KeyColumns     []string        `toml:"key_columns"`
+	Columns        []string        `toml:"columns"`
+	Overwrite      bool            `toml:"overwrite"`
+	ReloadInterval config.Duration `toml:"reload_interval"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	table     map[string]map[string]string
+	modTimes  []time.Time
+	nextCheck time.Time
+}
+
+func (l *Lookup) SampleConfig() string {
+	return sampleConfig
+}
+
+func (l *Lookup) Description() string {
+	return "Add tags looked up in a CSV or JSON table by tag values"
+}
+
+func (l *Lookup) Init() error {
+	if len(l.Files) == 0 {
+		return errors.New("no files specified")
+	}
+
+	switch l.Format {
+	case "":
+		l.Format = "csv"
+	case "csv", "json":
+	default:
+		return fmt.Errorf("invalid format %q", l.Format)
+	}
+
+	if len(l.KeyTags) == 0 {
+		return errors.New("no key tags specified")
+	}
+	if len(l.KeyColumns) == 0 {
+		l.KeyColumns = l.KeyTags
+	}
+	if len(l.KeyColumns) != len(l.KeyTags) {
+		return errors.New("number of key columns does not match the number of key tags")
+	}
+
+	return l.load()
+}
+
+// load reads all files and replaces the table if they were read
+// successfully.
+func (l *Lookup) load() error {
+	table := make(map[string]map[string]string)
+	modTimes := make([]time.Time, len(l.Files))
+	for i, filename := range l.Files {
+		modTime, err := l.loadFile(filename, table)
+		if err != nil {
+			return fmt.Errorf("loading %q failed: %v", filename, err)
+		}
+		modTimes[i] = modTime
+	}
+
+	l.table = table
+	l.modTimes = modTimes
+	l.nextCheck = time.Now().Add(time.Duration(l.ReloadInterval))
+	return nil
+}
+
+func (l *Lookup) loadFile(filename string, table map[string]map[string]string) (time.Time, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var rows []map[string]string
+	switch l.Format {
+	case "csv":
+		rows, err = readCSV(f)
+	case "json":
+		rows, err = readJSON(f)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for i, row := range rows {
+		values := make([]string, 0, len(l.KeyColumns))
+		for _, column := range l.KeyColumns {
+			value, ok := row[column]
+			if !ok {
+				return time.Time{}, fmt.Errorf("entry %d: missing key column %q", i+1, column)
+			}
+			values = append(values, value)
+		}
+
+		tags := make(map[string]string)
+		if len(l.Columns) > 0 {
+			for _, column := range l.Columns {
+				if value := row[column]; value != "" {
+					tags[column] = value
+				}
+			}
+		} else {
+			for column, value := range row {
+				if value != "" && !l.isKeyColumn(column) {
+					tags[column] = value
+				}
+			}
+		}
+		table[strings.Join(values, keySeparator)] = tags
+	}
+	return stat.ModTime(), nil
+}
+
+func (l *Lookup) isKeyColumn(column string) bool {
+	for _, key := range l.KeyColumns {
+		if column == key {
+			return true
+		}
+	}
+	return false
+}
+
+func readCSV(r io.Reader) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("missing header")
+		}
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]string, len(header))
+		for i, column := range header {
+			row[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func readJSON(r io.Reader) ([]map[string]string, error) {
+	var entries []map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	rows := make([]map[string]string, 0, len(entries))
+	for i, entry := range entries {
+		row := make(map[string]string, len(entry))
+		for column, value := range entry {
+			switch v := value.(type) {
+			case nil:
+			case string:
+				row[column] = v
+			case bool:
+				row[column] = strconv.FormatBool(v)
+			case float64:
+				row[column] = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				return nil, fmt.Errorf("entry %d: invalid type %T of column %q", i+1, value, column)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// reload loads the files again if any of them was modified.  On errors the
+// previous table is kept.
+func (l *Lookup) reload() {
+	now := time.Now()
+	if l.ReloadInterval <= 0 || now.Before(l.nextCheck) {
+		return
+	}
+	l.nextCheck = now.Add(time.Duration(l.ReloadInterval))
+
+	modified := false
+	for i, filename := range l.Files {
+		stat, err := os.Stat(filename)
+		if err != nil {
+			l.Log.Errorf("Checking %q failed: %v", filename, err)
+			return
+		}
+		if !stat.ModTime().Equal(l.modTimes[i]) {
+			modified = true
+		}
+	}
+	if !modified {
+		return
+	}
+
+	if err := l.load(); err != nil {
+		l.Log.Errorf("Reloading failed, keeping the previous table: %v", err)
+		return
+	}
+	l.Log.Debugf("Reloaded %d entries", len(l.table))
+}
+
+func (l *Lookup) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	l.reload()
+
+	values := make([]string, len(l.KeyTags))
+	for _, m := range in {
+		found := true
+		for i, key := range l.KeyTags {
+			value, ok := m.GetTag(key)
+			if !ok {
+				found = false
+				break
+			}
+			values[i] = value
+		}
+		if !found {
+			continue
+		}
+
+		tags, ok := l.table[strings.Join(values, keySeparator)]
+		if !ok {
+			continue
+		}
+		for key, value := range tags {
+			if !l.Overwrite && m.HasTag(key) {
+				continue
+			}
+			m.AddTag(key, value)
+		}
+	}
+	return in
+}
+
+func init() {
+	processors.Add("lookup", func() telegraf.Processor {
+		return &Lookup{
+			ReloadInterval: config.Duration(time.Minute),
+		}
+	})
+}
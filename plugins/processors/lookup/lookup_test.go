@@ -0,0 +1,234 @@
+package lookup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/config"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func cpu(tags map[string]string) telegraf.Metric {
+	return testutil.MustMetric("cpu",
+		tags,
+		map[string]interface{}{"usage_idle": 42.0},
+		time.Unix(0, 0))
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugin   *Lookup
+		input    []telegraf.Metric
+		expected []telegraf.Metric
+	}{
+		{
+			name: "csv",
+			plugin: &Lookup{
+				Files:   []string{"testdata/inventory.csv"},
+				KeyTags: []string{"host"},
+			},
+			input: []telegraf.Metric{
+				cpu(map[string]string{"host": "web01"}),
+				cpu(map[string]string{"host": "web02"}),
+				cpu(map[string]string{"host": "unknown"}),
+				cpu(map[string]string{}),
+			},
+			expected: []telegraf.Metric{
+				cpu(map[string]string{"host": "web01", "team": "frontend", "rack": "r1", "environment": "production"}),
+				cpu(map[string]string{"host": "web02", "team": "frontend", "rack": "r2"}),
+				cpu(map[string]string{"host": "unknown"}),
+				cpu(map[string]string{}),
+			},
+		},
+		{
+			name: "json",
+			plugin: &Lookup{
+				Files:   []string{"testdata/inventory.json"},
+				Format:  "json",
+				KeyTags: []string{"host"},
+			},
+			input: []telegraf.Metric{
+				cpu(map[string]string{"host": "web02"}),
+				cpu(map[string]string{"host": "db01"}),
+			},
+			expected: []telegraf.Metric{
+				cpu(map[string]string{"host": "web02", "team": "frontend", "rack": "2"}),
+				cpu(map[string]string{"host": "db01", "team": "database", "rack": "1", "environment": "production"}),
+			},
+		},
+		{
+			name: "selected columns",
+			plugin: &Lookup{
+				Files:   []string{"testdata/inventory.csv"},
+				KeyTags: []string{"host"},
+				Columns: []string{"team"},
+			},
+			input: []telegraf.Metric{
+				cpu(map[string]string{"host": "db01"}),
+			},
+			expected: []telegraf.Metric{
+				cpu(map[string]string{"host": "db01", "team": "database"}),
+			},
+		},
+		{
+			name: "overwrite",
+			plugin: &Lookup{
+				Files:     []string{"testdata/inventory.csv"},
+				KeyTags:   []string{"host"},
+				Overwrite: true,
+			},
+			input: []telegraf.Metric{
+				cpu(map[string]string{"host": "db01", "team": "dba"}),
+			},
+			expected: []telegraf.Metric{
+				cpu(map[string]string{"host": "db01", "team": "database", "rack": "r1", "environment": "production"}),
+			},
+		},
+		{
+			name: "keep existing tags",
+			plugin: &Lookup{
+				Files:   []string{"testdata/inventory.csv"},
+				KeyTags: []string{"host"},
+			},
+			input: []telegraf.Metric{
+				cpu(map[string]string{"host": "db01", "team": "dba"}),
+			},
+			expected: []telegraf.Metric{
+				cpu(map[string]string{"host": "db01", "team": "dba", "rack": "r1", "environment": "production"}),
+			},
+		},
+		{
+			name: "multiple keys",
+			plugin: &Lookup{
+				Files:      []string{"testdata/interfaces.csv"},
+				KeyTags:    []string{"agent_host", "ifIndex"},
+				KeyColumns: []string{"agent", "ifIndex"},
+			},
+			input: []telegraf.Metric{
+				cpu(map[string]string{"agent_host": "10.0.0.1", "ifIndex": "2"}),
+				cpu(map[string]string{"agent_host": "10.0.0.2", "ifIndex": "2"}),
+				cpu(map[string]string{"agent_host": "10.0.0.2"}),
+			},
+			expected: []telegraf.Metric{
+				cpu(map[string]string{"agent_host": "10.0.0.1", "ifIndex": "2", "circuit": "uplink-b"}),
+				cpu(map[string]string{"agent_host": "10.0.0.2", "ifIndex": "2"}),
+				cpu(map[string]string{"agent_host": "10.0.0.2"}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.plugin.Log = testutil.Logger{}
+			require.NoError(t, tt.plugin.Init())
+			actual := tt.plugin.Apply(tt.input...)
+			testutil.RequireMetricsEqual(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestInitError(t *testing.T) {
+	tests := []struct {
+		name   string
+		plugin *Lookup
+	}{
+		{
+			name:   "no files",
+			plugin: &Lookup{KeyTags: []string{"host"}},
+		},
+		{
+			name:   "no key tags",
+			plugin: &Lookup{Files: []string{"testdata/inventory.csv"}},
+		},
+		{
+			name: "invalid format",
+			plugin: &Lookup{
+				Files:   []string{"testdata/inventory.csv"},
+				Format:  "xml",
+				KeyTags: []string{"host"},
+			},
+		},
+		{
+			name: "key column count",
+			plugin: &Lookup{
+				Files:      []string{"testdata/inventory.csv"},
+				KeyTags:    []string{"host"},
+				KeyColumns: []string{"host", "rack"},
+			},
+		},
+		{
+			name: "missing key column",
+			plugin: &Lookup{
+				Files:   []string{"testdata/inventory.csv"},
+				KeyTags: []string{"hostname"},
+			},
+		},
+		{
+			name: "missing file",
+			plugin: &Lookup{
+				Files:   []string{"testdata/missing.csv"},
+				KeyTags: []string{"host"},
+			},
+		},
+		{
+			name: "invalid json",
+			plugin: &Lookup{
+				Files:   []string{"testdata/inventory.csv"},
+				Format:  "json",
+				KeyTags: []string{"host"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.plugin.Log = testutil.Logger{}
+			require.Error(t, tt.plugin.Init())
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lookup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "inventory.csv")
+	writeTable := func(content string, modTime time.Time) {
+		require.NoError(t, ioutil.WriteFile(filename, []byte(content), 0644))
+		require.NoError(t, os.Chtimes(filename, modTime, modTime))
+	}
+	writeTable("host,team\nweb01,frontend\n", time.Unix(1000, 0))
+
+	plugin := &Lookup{
+		Files:          []string{filename},
+		KeyTags:        []string{"host"},
+		ReloadInterval: config.Duration(time.Nanosecond),
+		Log:            testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	actual := plugin.Apply(cpu(map[string]string{"host": "web01"}))
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{
+		cpu(map[string]string{"host": "web01", "team": "frontend"}),
+	}, actual)
+
+	writeTable("host,team\nweb01,platform\n", time.Unix(2000, 0))
+	actual = plugin.Apply(cpu(map[string]string{"host": "web01"}))
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{
+		cpu(map[string]string{"host": "web01", "team": "platform"}),
+	}, actual)
+
+	// An invalid file keeps the previous table.
+	writeTable("team\nbackend\n", time.Unix(3000, 0))
+	actual = plugin.Apply(cpu(map[string]string{"host": "web01"}))
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{
+		cpu(map[string]string{"host": "web01", "team": "platform"}),
+	}, actual)
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/shanas-swi/telegraf-v1.16.3"
+	common "github.com/shanas-swi/telegraf-v1.16.3/plugins/common/starlark"
 	"github.com/shanas-swi/telegraf-v1.16.3/plugins/parsers"
 	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
 	"github.com/stretchr/testify/require"
@@ -24,60 +25,74 @@ func TestInitError(t *testing.T) {
 		{
 			name: "source must define apply",
 			plugin: &Starlark{
-				Source: "",
-				Log:    testutil.Logger{},
+				StarlarkCommon: common.StarlarkCommon{
+					Source: "",
+					Log:    testutil.Logger{},
+				},
 			},
 		},
 		{
 			name: "apply must be a function",
 			plugin: &Starlark{
-				Source: `
+				StarlarkCommon: common.StarlarkCommon{
+					Source: `
 apply = 42
 `,
-				Log: testutil.Logger{},
+					Log: testutil.Logger{},
+				},
 			},
 		},
 		{
 			name: "apply function must take one arg",
 			plugin: &Starlark{
-				Source: `
+				StarlarkCommon: common.StarlarkCommon{
+					Source: `
 def apply():
 	pass
 `,
-				Log: testutil.Logger{},
+					Log: testutil.Logger{},
+				},
 			},
 		},
 		{
 			name: "package scope must have valid syntax",
 			plugin: &Starlark{
-				Source: `
+				StarlarkCommon: common.StarlarkCommon{
+					Source: `
 for
 `,
-				Log: testutil.Logger{},
+					Log: testutil.Logger{},
+				},
 			},
 		},
 		{
 			name: "no source no script",
 			plugin: &Starlark{
-				Log: testutil.Logger{},
+				StarlarkCommon: common.StarlarkCommon{
+					Log: testutil.Logger{},
+				},
 			},
 		},
 		{
 			name: "source and script",
 			plugin: &Starlark{
-				Source: `
+				StarlarkCommon: common.StarlarkCommon{
+					Source: `
 def apply():
 	pass
 `,
-				Script: "testdata/ratio.star",
-				Log:    testutil.Logger{},
+					Script: "testdata/ratio.star",
+					Log:    testutil.Logger{},
+				},
 			},
 		},
 		{
 			name: "script file not found",
 			plugin: &Starlark{
-				Script: "testdata/file_not_found.star",
-				Log:    testutil.Logger{},
+				StarlarkCommon: common.StarlarkCommon{
+					Script: "testdata/file_not_found.star",
+					Log:    testutil.Logger{},
+				},
 			},
 		},
 	}
@@ -217,8 +232,10 @@ def apply(metric):
 	for _, tt := range applyTests {
 		t.Run(tt.name, func(t *testing.T) {
 			plugin := &Starlark{
-				Source: tt.source,
-				Log:    testutil.Logger{},
+				StarlarkCommon: common.StarlarkCommon{
+					Source: tt.source,
+					Log:    testutil.Logger{},
+				},
 			}
 			err := plugin.Init()
 			require.NoError(t, err)
@@ -2423,8 +2440,10 @@ def process(metric):
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			plugin := &Starlark{
-				Source: tt.source,
-				Log:    testutil.Logger{},
+				StarlarkCommon: common.StarlarkCommon{
+					Source: tt.source,
+					Log:    testutil.Logger{},
+				},
 			}
 			err := plugin.Init()
 			require.NoError(t, err)
@@ -2462,8 +2481,10 @@ func TestScript(t *testing.T) {
 		{
 			name: "rename",
 			plugin: &Starlark{
-				Script: "testdata/rename.star",
-				Log:    testutil.Logger{},
+				StarlarkCommon: common.StarlarkCommon{
+					Script: "testdata/rename.star",
+					Log:    testutil.Logger{},
+				},
 			},
 			input: []telegraf.Metric{
 				testutil.MustMetric("cpu",
@@ -2489,8 +2510,10 @@ func TestScript(t *testing.T) {
 		{
 			name: "drop fields by type",
 			plugin: &Starlark{
-				Script: "testdata/drop_string_fields.star",
-				Log:    testutil.Logger{},
+				StarlarkCommon: common.StarlarkCommon{
+					Script: "testdata/drop_string_fields.star",
+					Log:    testutil.Logger{},
+				},
 			},
 			input: []telegraf.Metric{
 				testutil.MustMetric("device",
@@ -2520,8 +2543,10 @@ func TestScript(t *testing.T) {
 		{
 			name: "drop fields with unexpected type",
 			plugin: &Starlark{
-				Script: "testdata/drop_fields_with_unexpected_type.star",
-				Log:    testutil.Logger{},
+				StarlarkCommon: common.StarlarkCommon{
+					Script: "testdata/drop_fields_with_unexpected_type.star",
+					Log:    testutil.Logger{},
+				},
 			},
 			input: []telegraf.Metric{
 				testutil.MustMetric("device",
@@ -2554,8 +2579,10 @@ func TestScript(t *testing.T) {
 		{
 			name: "scale",
 			plugin: &Starlark{
-				Script: "testdata/scale.star",
-				Log:    testutil.Logger{},
+				StarlarkCommon: common.StarlarkCommon{
+					Script: "testdata/scale.star",
+					Log:    testutil.Logger{},
+				},
 			},
 			input: []telegraf.Metric{
 				testutil.MustMetric("cpu",
@@ -2575,8 +2602,10 @@ func TestScript(t *testing.T) {
 		{
 			name: "ratio",
 			plugin: &Starlark{
-				Script: "testdata/ratio.star",
-				Log:    testutil.Logger{},
+				StarlarkCommon: common.StarlarkCommon{
+					Script: "testdata/ratio.star",
+					Log:    testutil.Logger{},
+				},
 			},
 			input: []telegraf.Metric{
 				testutil.MustMetric("mem",
@@ -2603,8 +2632,10 @@ func TestScript(t *testing.T) {
 		{
 			name: "logging",
 			plugin: &Starlark{
-				Script: "testdata/logging.star",
-				Log:    testutil.Logger{},
+				StarlarkCommon: common.StarlarkCommon{
+					Script: "testdata/logging.star",
+					Log:    testutil.Logger{},
+				},
 			},
 			input: []telegraf.Metric{
 				testutil.MustMetric("log",
@@ -2628,8 +2659,10 @@ func TestScript(t *testing.T) {
 		{
 			name: "multiple_metrics",
 			plugin: &Starlark{
-				Script: "testdata/multiple_metrics.star",
-				Log:    testutil.Logger{},
+				StarlarkCommon: common.StarlarkCommon{
+					Script: "testdata/multiple_metrics.star",
+					Log:    testutil.Logger{},
+				},
 			},
 			input: []telegraf.Metric{
 				testutil.MustMetric("mm",
@@ -2660,8 +2693,10 @@ func TestScript(t *testing.T) {
 		{
 			name: "multiple_metrics_with_json",
 			plugin: &Starlark{
-				Script: "testdata/multiple_metrics_with_json.star",
-				Log:    testutil.Logger{},
+				StarlarkCommon: common.StarlarkCommon{
+					Script: "testdata/multiple_metrics_with_json.star",
+					Log:    testutil.Logger{},
+				},
 			},
 			input: []telegraf.Metric{
 				testutil.MustMetric("json",
@@ -2692,8 +2727,10 @@ func TestScript(t *testing.T) {
 		{
 			name: "fail",
 			plugin: &Starlark{
-				Script: "testdata/fail.star",
-				Log:    testutil.Logger{},
+				StarlarkCommon: common.StarlarkCommon{
+					Script: "testdata/fail.star",
+					Log:    testutil.Logger{},
+				},
 			},
 			input: []telegraf.Metric{
 				testutil.MustMetric("fail",
@@ -2981,8 +3018,10 @@ def apply(metric):
 	for _, tt := range tests {
 		b.Run(tt.name, func(b *testing.B) {
 			plugin := &Starlark{
-				Source: tt.source,
-				Log:    testutil.Logger{},
+				StarlarkCommon: common.StarlarkCommon{
+					Source: tt.source,
+					Log:    testutil.Logger{},
+				},
 			}
 
 			err := plugin.Init()
@@ -3006,12 +3045,240 @@ def apply(metric):
 	}
 }
 
+func TestState(t *testing.T) {
+	plugin := &Starlark{
+		StarlarkCommon: common.StarlarkCommon{
+			Source: `
+def apply(metric):
+	count = state.get("count", 0) + 1
+	state["count"] = count
+	metric.fields["count"] = count
+	if "first" not in state:
+		state["first"] = deepcopy(metric)
+	metric.fields["first"] = state["first"].fields["value"]
+	return metric
+`,
+			Log: testutil.Logger{},
+		},
+	}
+	require.NoError(t, plugin.Init())
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, plugin.Start(acc))
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, plugin.Add(testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"value": int64(i * 10)},
+			time.Unix(0, 0)), acc))
+	}
+	require.NoError(t, plugin.Stop())
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"value": 10, "count": 1, "first": 10},
+			time.Unix(0, 0)),
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"value": 20, "count": 2, "first": 10},
+			time.Unix(0, 0)),
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"value": 30, "count": 3, "first": 10},
+			time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestModules(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected interface{}
+	}{
+		{
+			name:     "math floor",
+			source:   `load("math.star", "math"); result = math.floor(-2.5)`,
+			expected: int64(-3),
+		},
+		{
+			name:     "math ceil of int",
+			source:   `load("math.star", "math"); result = math.ceil(7)`,
+			expected: int64(7),
+		},
+		{
+			name:     "math log",
+			source:   `load("math.star", "math"); result = math.log(1000, 10)`,
+			expected: 2.9999999999999996,
+		},
+		{
+			name:     "math constants",
+			source:   `load("math.star", "math"); result = math.isinf(math.inf) and math.isnan(math.nan)`,
+			expected: true,
+		},
+		{
+			name:     "time duration arithmetic",
+			source:   `load("time.star", "time"); result = (time.parse_duration("1h30m") + 15 * time.minute).minutes`,
+			expected: 105.0,
+		},
+		{
+			name:     "time division",
+			source:   `load("time.star", "time"); result = time.hour // time.minute`,
+			expected: int64(60),
+		},
+		{
+			name: "time difference",
+			source: `
+load("time.star", "time")
+start = time.parse_time("2020-11-30T12:00:00Z")
+end = time.time(year=2020, month=11, day=30, hour=13, location="Europe/Berlin")
+result = (end - start).seconds`,
+			expected: 0.0,
+		},
+		{
+			name: "time attributes",
+			source: `
+load("time.star", "time")
+t = time.from_timestamp(1606737600, 5) + time.second
+result = "{} {} {}".format(t.unix, t.nanosecond, t.format("2006-01-02"))`,
+			expected: "1606737601 5 2020-11-30",
+		},
+		{
+			name: "time comparison",
+			source: `
+load("time.star", "time")
+t = time.now()
+result = t < t + time.nanosecond and t == time.from_timestamp(0, t.unix_nano)`,
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &Starlark{
+				StarlarkCommon: common.StarlarkCommon{
+					Source: tt.source + `
+def apply(metric):
+	metric.fields["result"] = result
+	return metric
+`,
+					Log: testutil.Logger{},
+				},
+			}
+			require.NoError(t, plugin.Init())
+
+			acc := &testutil.Accumulator{}
+			require.NoError(t, plugin.Start(acc))
+			require.NoError(t, plugin.Add(testutil.MustMetric("cpu",
+				map[string]string{},
+				map[string]interface{}{},
+				time.Unix(0, 0)), acc))
+			require.NoError(t, plugin.Stop())
+
+			require.Len(t, acc.Metrics, 1)
+			require.Equal(t, tt.expected, acc.Metrics[0].Fields["result"])
+		})
+	}
+}
+
+func TestLoadLibrary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "starlark")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"script.star": `
+load("lib.star", "double")
+def apply(metric):
+	metric.fields["value"] = double(metric.fields["value"])
+	return metric
+`,
+		"lib.star": `
+load("math.star", "math")
+def double(x):
+	return math.pow(x, 1) * 2
+`,
+		"cycle.star": `
+load("cycle_lib.star", "x")
+def apply(metric):
+	return metric
+`,
+		"cycle_lib.star": `
+load("cycle.star", "apply")
+x = 1
+`,
+		"missing.star": `
+load("not_existing.star", "x")
+def apply(metric):
+	return metric
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	plugin := &Starlark{
+		StarlarkCommon: common.StarlarkCommon{
+			Script: filepath.Join(dir, "script.star"),
+			Log:    testutil.Logger{},
+		},
+	}
+	require.NoError(t, plugin.Init())
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, plugin.Start(acc))
+	require.NoError(t, plugin.Add(testutil.MustMetric("cpu",
+		map[string]string{},
+		map[string]interface{}{"value": 21},
+		time.Unix(0, 0)), acc))
+	require.NoError(t, plugin.Stop())
+	require.Equal(t, 42.0, acc.Metrics[0].Fields["value"])
+
+	plugin = &Starlark{
+		StarlarkCommon: common.StarlarkCommon{
+			Script: filepath.Join(dir, "cycle.star"),
+			Log:    testutil.Logger{},
+		},
+	}
+	err = plugin.Init()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cycle in load graph")
+
+	plugin = &Starlark{
+		StarlarkCommon: common.StarlarkCommon{
+			Script: filepath.Join(dir, "missing.star"),
+			Log:    testutil.Logger{},
+		},
+	}
+	require.Error(t, plugin.Init())
+
+	plugin = &Starlark{
+		StarlarkCommon: common.StarlarkCommon{
+			Source: `
+load("os.py", "system")
+def apply(metric):
+	return metric
+`,
+			Log: testutil.Logger{},
+		},
+	}
+	require.EqualError(t, plugin.Init(), "cannot load os.py: module os.py is not available")
+}
+
 func TestAllScriptTestData(t *testing.T) {
 	// can be run from multiple folders
 	paths := []string{"testdata", "plugins/processors/starlark/testdata"}
 	for _, testdataPath := range paths {
 		filepath.Walk(testdataPath, func(path string, info os.FileInfo, err error) error {
-			if info == nil || info.IsDir() {
+			if info == nil {
+				return nil
+			}
+			if info.IsDir() {
+				// Libraries are loaded by the examples and not scripts on
+				// their own.
+				if info.Name() == "lib" {
+					return filepath.SkipDir
+				}
 				return nil
 			}
 			fn := path
@@ -3026,8 +3293,10 @@ func TestAllScriptTestData(t *testing.T) {
 					outputMetrics = parseMetricsFrom(t, lines, "Example Output:")
 				}
 				plugin := &Starlark{
-					Script: fn,
-					Log:    testutil.Logger{},
+					StarlarkCommon: common.StarlarkCommon{
+						Script: fn,
+						Log:    testutil.Logger{},
+					},
 				}
 				require.NoError(t, plugin.Init())
 
@@ -0,0 +1,86 @@
+package units
+
+// unit converts values from and to the base unit of its dimension, the value
+// in the base unit is value * factor + offset.
+type unit struct {
+	dimension string
+	factor    float64
+	offset    float64
+}
+
+func (u unit) toBase(v float64) float64 {
+	return v*u.factor + u.offset
+}
+
+func (u unit) fromBase(v float64) float64 {
+	return (v - u.offset) / u.factor
+}
+
+const (
+	kilo = 1e3
+	mega = 1e6
+	giga = 1e9
+	tera = 1e12
+	peta = 1e15
+
+	kibi = 1 << 10
+	mebi = 1 << 20
+	gibi = 1 << 30
+	tebi = 1 << 40
+	pebi = 1 << 50
+)
+
+// units maps the unit names to their definition.  The base units are bytes,
+// seconds, Kelvin, bytes per second and Hertz.
+var units = map[string]unit{
+	// Data size
+	"bit":  {dimension: "data size", factor: 1.0 / 8},
+	"kbit": {dimension: "data size", factor: kilo / 8},
+	"Mbit": {dimension: "data size", factor: mega / 8},
+	"Gbit": {dimension: "data size", factor: giga / 8},
+	"Tbit": {dimension: "data size", factor: tera / 8},
+	"B":    {dimension: "data size", factor: 1},
+	"kB":   {dimension: "data size", factor: kilo},
+	"MB":   {dimension: "data size", factor: mega},
+	"GB":   {dimension: "data size", factor: giga},
+	"TB":   {dimension: "data size", factor: tera},
+	"PB":   {dimension: "data size", factor: peta},
+	"KiB":  {dimension: "data size", factor: kibi},
+	"MiB":  {dimension: "data size", factor: mebi},
+	"GiB":  {dimension: "data size", factor: gibi},
+	"TiB":  {dimension: "data size", factor: tebi},
+	"PiB":  {dimension: "data size", factor: pebi},
+
+	// Time
+	"ns":  {dimension: "time", factor: 1e-9},
+	"us":  {dimension: "time", factor: 1e-6},
+	"ms":  {dimension: "time", factor: 1e-3},
+	"s":   {dimension: "time", factor: 1},
+	"min": {dimension: "time", factor: 60},
+	"h":   {dimension: "time", factor: 60 * 60},
+	"d":   {dimension: "time", factor: 24 * 60 * 60},
+
+	// Temperature
+	"C": {dimension: "temperature", factor: 1, offset: 273.15},
+	"F": {dimension: "temperature", factor: 5.0 / 9, offset: 273.15 - 32*5.0/9},
+	"K": {dimension: "temperature", factor: 1},
+
+	// Data rate
+	"bit/s":  {dimension: "data rate", factor: 1.0 / 8},
+	"kbit/s": {dimension: "data rate", factor: kilo / 8},
+	"Mbit/s": {dimension: "data rate", factor: mega / 8},
+	"Gbit/s": {dimension: "data rate", factor: giga / 8},
+	"B/s":    {dimension: "data rate", factor: 1},
+	"kB/s":   {dimension: "data rate", factor: kilo},
+	"MB/s":   {dimension: "data rate", factor: mega},
+	"GB/s":   {dimension: "data rate", factor: giga},
+	"KiB/s":  {dimension: "data rate", factor: kibi},
+	"MiB/s":  {dimension: "data rate", factor: mebi},
+	"GiB/s":  {dimension: "data rate", factor: gibi},
+
+	// Frequency
+	"Hz":  {dimension: "frequency", factor: 1},
+	"kHz": {dimension: "frequency", factor: kilo},
+	"MHz": {dimension: "frequency", factor: mega},
+	"GHz": {dimension: "frequency", factor: giga},
+}
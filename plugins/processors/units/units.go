@@ -0,0 +1,228 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/filter"
+	"github.com/shanas-swi/telegraf-v1.16.3/plugins/processors"
+)
+
+var sampleConfig = `
+  ## Conversions are applied in order, each field is converted by the first
+  ## matching conversion only.
+  [[processors.units.conversion]]
+    ## Fields to convert, may contain globs.
+    fields = ["*_bytes"]
+
+    ## Units to convert between, both units must be of the same dimension:
+    ##   data size:   bit, kbit, Mbit, Gbit, Tbit, B, kB, MB, GB, TB, PB,
+    ##                KiB, MiB, GiB, TiB, PiB
+    ##   time:        ns, us, ms, s, min, h, d
+    ##   temperature: C, F, K
+    ##   data rate:   bit/s, kbit/s, Mbit/s, Gbit/s, B/s, kB/s, MB/s, GB/s,
+    ##                KiB/s, MiB/s, GiB/s
+    ##   frequency:   Hz, kHz, MHz, GHz
+    from = "KiB"
+    to = "B"
+
+  [[processors.units.conversion]]
+    fields = ["level"]
+
+    ## Linear mapping applied instead of a unit conversion:
+    ##   value * scale + offset
+    scale = 0.1
+    # offset = 0.0
+
+    ## Limits of the converted value, values outside of the range are set to
+    ## the limit.
+    # min = 0.0
+    # max = 100.0
+
+    ## Type of the converted field, one of "float", "integer", "unsigned" or
+    ## "input" to keep the type of the input field.  Values are rounded to the
+    ## nearest integer and limited to the range of the integer types.
+    # output_type = "float"
+`
+
+type Conversion struct {
+	Fields []string `toml:"fields"`
+	From   string   `toml:"from"`
+	To     string   `toml:"to"`
+	Scale  *float64 `toml:"scale"`
+	Offset float64  `toml:"offset"`
+	Min    *float64 `toml:"min"`
+	Max    *float64 `toml:"max"`
+
+	OutputType string `toml:"output_type"`
+
+	filter   filter.Filter
+	from, to unit
+}
+
+type Units struct {
+	Conversions []*Conversion `toml:"conversion"`
+
+	Log telegraf.Logger `toml:"-"`
+}
+
+func (u *Units) SampleConfig() string {
+	return sampleConfig
+}
+
+func (u *Units) Description() string {
+	return "Convert fields between units or scale them linearly"
+}
+
+func (u *Units) Init() error {
+	for i, c := range u.Conversions {
+		if err := c.init(); err != nil {
+			return fmt.Errorf("conversion %d: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (c *Conversion) init() error {
+	if len(c.Fields) == 0 {
+		return errors.New("no fields specified")
+	}
+	var err error
+	c.filter, err = filter.Compile(c.Fields)
+	if err != nil {
+		return err
+	}
+
+	if c.From != "" || c.To != "" {
+		if c.Scale != nil || c.Offset != 0 {
+			return errors.New("units cannot be combined with scale and offset")
+		}
+
+		var ok bool
+		c.from, ok = units[c.From]
+		if !ok {
+			return fmt.Errorf("unknown unit %q", c.From)
+		}
+		c.to, ok = units[c.To]
+		if !ok {
+			return fmt.Errorf("unknown unit %q", c.To)
+		}
+		if c.from.dimension != c.to.dimension {
+			return fmt.Errorf("cannot convert %s in %q to %s in %q", c.from.dimension, c.From, c.to.dimension, c.To)
+		}
+	} else {
+		// The linear mapping is expressed as conversion from a unit to the
+		// base unit.
+		scale := 1.0
+		if c.Scale != nil {
+			scale = *c.Scale
+		}
+		c.from = unit{factor: scale, offset: c.Offset}
+		c.to = unit{factor: 1}
+	}
+
+	if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+		return errors.New("min must not be greater than max")
+	}
+
+	switch c.OutputType {
+	case "":
+		c.OutputType = "float"
+	case "float", "integer", "unsigned", "input":
+	default:
+		return fmt.Errorf("unknown output type %q", c.OutputType)
+	}
+	return nil
+}
+
+func (c *Conversion) convert(v float64) float64 {
+	v = c.to.fromBase(c.from.toBase(v))
+	if c.Min != nil {
+		v = math.Max(v, *c.Min)
+	}
+	if c.Max != nil {
+		v = math.Min(v, *c.Max)
+	}
+	return v
+}
+
+// output returns the converted value in the output type, input is the value
+// of the field before the conversion.
+func (c *Conversion) output(v float64, input interface{}) interface{} {
+	typ := c.OutputType
+	if typ == "input" {
+		switch input.(type) {
+		case int64:
+			typ = "integer"
+		case uint64:
+			typ = "unsigned"
+		default:
+			typ = "float"
+		}
+	}
+
+	switch typ {
+	case "integer":
+		v = math.Round(v)
+		if v >= math.MaxInt64 {
+			return int64(math.MaxInt64)
+		}
+		if v <= math.MinInt64 {
+			return int64(math.MinInt64)
+		}
+		return int64(v)
+	case "unsigned":
+		v = math.Round(v)
+		if v >= math.MaxUint64 {
+			return uint64(math.MaxUint64)
+		}
+		if v <= 0 {
+			return uint64(0)
+		}
+		return uint64(v)
+	default:
+		return v
+	}
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func (u *Units) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	for _, m := range in {
+		for _, field := range m.FieldList() {
+			for _, c := range u.Conversions {
+				if !c.filter.Match(field.Key) {
+					continue
+				}
+
+				v, ok := toFloat(field.Value)
+				if !ok {
+					u.Log.Debugf("Cannot convert non-numeric field %q of %q", field.Key, m.Name())
+				} else {
+					m.AddField(field.Key, c.output(c.convert(v), field.Value))
+				}
+				break
+			}
+		}
+	}
+	return in
+}
+
+func init() {
+	processors.Add("units", func() telegraf.Processor {
+		return &Units{}
+	})
+}
@@ -0,0 +1,218 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/shanas-swi/telegraf-v1.16.3"
+	"github.com/shanas-swi/telegraf-v1.16.3/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func TestConvertUnits(t *testing.T) {
+	tests := []struct {
+		from     string
+		to       string
+		value    interface{}
+		expected float64
+	}{
+		{from: "KiB", to: "B", value: int64(2), expected: 2048},
+		{from: "B", to: "MB", value: uint64(1500000), expected: 1.5},
+		{from: "Mbit", to: "MB", value: 8.0, expected: 1},
+		{from: "GiB", to: "GB", value: 1.0, expected: 1.073741824},
+		{from: "ms", to: "s", value: int64(1500), expected: 1.5},
+		{from: "d", to: "h", value: 0.5, expected: 12},
+		{from: "ns", to: "us", value: int64(1), expected: 0.001},
+		{from: "C", to: "F", value: 100.0, expected: 212},
+		{from: "F", to: "C", value: int64(-40), expected: -40},
+		{from: "K", to: "C", value: 0.0, expected: -273.15},
+		{from: "F", to: "K", value: 32.0, expected: 273.15},
+		{from: "Mbit/s", to: "B/s", value: 1.0, expected: 125000},
+		{from: "KiB/s", to: "kbit/s", value: 1.0, expected: 8.192},
+		{from: "GHz", to: "MHz", value: 2.4, expected: 2400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			plugin := &Units{
+				Conversions: []*Conversion{
+					{Fields: []string{"value"}, From: tt.from, To: tt.to},
+				},
+				Log: testutil.Logger{},
+			}
+			require.NoError(t, plugin.Init())
+
+			actual := plugin.Apply(testutil.MustMetric("test",
+				map[string]string{},
+				map[string]interface{}{"value": tt.value},
+				time.Unix(0, 0)))
+			expected := []telegraf.Metric{
+				testutil.MustMetric("test",
+					map[string]string{},
+					map[string]interface{}{"value": tt.expected},
+					time.Unix(0, 0)),
+			}
+			testutil.RequireMetricsEqual(t, expected, actual, cmpopts.EquateApprox(0, 1e-9))
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	plugin := &Units{
+		Conversions: []*Conversion{
+			{Fields: []string{"temp_*"}, From: "F", To: "C", Min: float(-50)},
+			{Fields: []string{"level", "temp_raw"}, Scale: float(0.1), Min: float(0), Max: float(100)},
+			{Fields: []string{"offset"}, Offset: -10},
+			{Fields: []string{"*_kb"}, From: "kB", To: "B"},
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	input := []telegraf.Metric{
+		testutil.MustMetric("sensors",
+			map[string]string{},
+			map[string]interface{}{
+				"temp_inlet": 212.0,
+				"temp_raw":   -1000.0,
+				"level":      int64(1200),
+				"offset":     uint64(15),
+				"used_kb":    int64(3),
+				"state":      "ok",
+				"other":      int64(3),
+			},
+			time.Unix(0, 0)),
+		testutil.MustMetric("sensors",
+			map[string]string{},
+			map[string]interface{}{
+				"level":   int64(-5),
+				"used_kb": "invalid",
+			},
+			time.Unix(0, 0)),
+	}
+	expected := []telegraf.Metric{
+		testutil.MustMetric("sensors",
+			map[string]string{},
+			map[string]interface{}{
+				"temp_inlet": 100.0,
+				// Only the first matching conversion is applied.
+				"temp_raw": -50.0,
+				"level":    100.0,
+				"offset":   5.0,
+				"used_kb":  3000.0,
+				"state":    "ok",
+				"other":    int64(3),
+			},
+			time.Unix(0, 0)),
+		testutil.MustMetric("sensors",
+			map[string]string{},
+			map[string]interface{}{
+				"level":   0.0,
+				"used_kb": "invalid",
+			},
+			time.Unix(0, 0)),
+	}
+
+	actual := plugin.Apply(input...)
+	testutil.RequireMetricsEqual(t, expected, actual, cmpopts.EquateApprox(0, 1e-9))
+}
+
+func TestOutputType(t *testing.T) {
+	plugin := &Units{
+		Conversions: []*Conversion{
+			{Fields: []string{"*_kb"}, From: "kB", To: "B", OutputType: "input"},
+			{Fields: []string{"*_ms"}, From: "ms", To: "s", OutputType: "input"},
+			{Fields: []string{"*_f"}, From: "F", To: "C", OutputType: "integer"},
+			{Fields: []string{"level"}, Scale: float(-1), OutputType: "unsigned"},
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	input := testutil.MustMetric("test",
+		map[string]string{},
+		map[string]interface{}{
+			"used_kb":    int64(3),
+			"free_kb":    uint64(2),
+			"ratio_kb":   0.5,
+			"latency_ms": int64(1500),
+			"temp_f":     212.5,
+			"level":      int64(5),
+		},
+		time.Unix(0, 0))
+	expected := []telegraf.Metric{
+		testutil.MustMetric("test",
+			map[string]string{},
+			map[string]interface{}{
+				"used_kb":    int64(3000),
+				"free_kb":    uint64(2000),
+				"ratio_kb":   500.0,
+				"latency_ms": int64(2),
+				"temp_f":     int64(100),
+				"level":      uint64(0),
+			},
+			time.Unix(0, 0)),
+	}
+
+	actual := plugin.Apply(input)
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestInitError(t *testing.T) {
+	tests := []struct {
+		name       string
+		conversion *Conversion
+		err        string
+	}{
+		{
+			name:       "no fields",
+			conversion: &Conversion{From: "B", To: "kB"},
+			err:        "conversion 1: no fields specified",
+		},
+		{
+			name:       "unknown unit",
+			conversion: &Conversion{Fields: []string{"value"}, From: "B", To: "kb"},
+			err:        `conversion 1: unknown unit "kb"`,
+		},
+		{
+			name:       "missing unit",
+			conversion: &Conversion{Fields: []string{"value"}, From: "B"},
+			err:        `conversion 1: unknown unit ""`,
+		},
+		{
+			name:       "different dimensions",
+			conversion: &Conversion{Fields: []string{"value"}, From: "B", To: "B/s"},
+			err:        `conversion 1: cannot convert data size in "B" to data rate in "B/s"`,
+		},
+		{
+			name:       "units and scale",
+			conversion: &Conversion{Fields: []string{"value"}, From: "B", To: "kB", Scale: float(2)},
+			err:        "conversion 1: units cannot be combined with scale and offset",
+		},
+		{
+			name:       "invalid range",
+			conversion: &Conversion{Fields: []string{"value"}, Min: float(1), Max: float(0)},
+			err:        "conversion 1: min must not be greater than max",
+		},
+		{
+			name:       "unknown output type",
+			conversion: &Conversion{Fields: []string{"value"}, From: "B", To: "kB", OutputType: "int"},
+			err:        `conversion 1: unknown output type "int"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &Units{
+				Conversions: []*Conversion{tt.conversion},
+				Log:         testutil.Logger{},
+			}
+			require.EqualError(t, plugin.Init(), tt.err)
+		})
+	}
+}